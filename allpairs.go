@@ -0,0 +1,161 @@
+package grapho
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ichinaski/grapho/container"
+)
+
+// DistanceMatrix holds the shortest paths between every pair of nodes of a Graph.
+// Rows and columns follow the Index, which binds matrix positions to node ids.
+type DistanceMatrix struct {
+	Index *Index
+	dist  [][]int // dist[i][j] is the cost of the shortest path from i to j
+	pred  [][]int // pred[i][j] is the predecessor of j in the path from i to j, or -1 if there is no path
+}
+
+func newDistanceMatrix(x *Index) *DistanceMatrix {
+	n := x.Len()
+	m := &DistanceMatrix{
+		Index: x,
+		dist:  make([][]int, n),
+		pred:  make([][]int, n),
+	}
+	for i := 0; i < n; i++ {
+		m.dist[i] = make([]int, n)
+		m.pred[i] = make([]int, n)
+		for j := range m.pred[i] {
+			m.pred[i][j] = -1
+		}
+		m.pred[i][i] = i
+	}
+	return m
+}
+
+// Dist returns the cost of the shortest path between u and v, or false if there is no such path
+func (m *DistanceMatrix) Dist(u, v uint64) (int, bool) {
+	i, oku := m.Index.Pos(u)
+	j, okv := m.Index.Pos(v)
+	if !oku || !okv || m.pred[i][j] < 0 {
+		return 0, false
+	}
+	return m.dist[i][j], true
+}
+
+// Path returns the shortest path between u and v, both included
+func (m *DistanceMatrix) Path(u, v uint64) ([]uint64, error) {
+	i, ok := m.Index.Pos(u)
+	if !ok {
+		return nil, fmt.Errorf("Node %d not found", u)
+	}
+	j, ok := m.Index.Pos(v)
+	if !ok {
+		return nil, fmt.Errorf("Node %d not found", v)
+	}
+	if m.pred[i][j] < 0 {
+		return nil, errors.New("Path not found")
+	}
+	return buildPath(u, v, func(node uint64) uint64 {
+		k, _ := m.Index.Pos(node)
+		return m.Index.ID(m.pred[i][k])
+	}), nil
+}
+
+// FloydWarshall computes the shortest paths between every pair of nodes, in O(n^3) time and O(n^2) space,
+// which suits dense graphs. Negative weights are supported, but if the Graph holds a negative cycle
+// (any negative edge, in undirected graphs), a *NegativeCycleError is returned.
+func FloydWarshall(g *Graph) (*DistanceMatrix, error) {
+	x := g.Index()
+	m := newDistanceMatrix(x)
+	n := x.Len()
+
+	for i, succ := range g.adjacency(x) {
+		for _, j := range succ {
+			weight := g.edges[x.ID(i)][x.ID(j)].Weight
+			if i != j || weight < 0 {
+				m.dist[i][j], m.pred[i][j] = weight, i
+			}
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			if m.pred[i][k] < 0 {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if m.pred[k][j] < 0 {
+					continue
+				}
+				if d := m.dist[i][k] + m.dist[k][j]; m.pred[i][j] < 0 || d < m.dist[i][j] {
+					m.dist[i][j], m.pred[i][j] = d, m.pred[k][j]
+				}
+			}
+		}
+	}
+
+	for i := 0; i < n; i++ {
+		if m.dist[i][i] < 0 {
+			// The node is in a negative cycle. Let Bellman-Ford find it
+			if _, _, err := bellmanFord(g, x.ID(i)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// Johnson computes the shortest paths between every pair of nodes, in O(nm log n) time, which suits
+// sparse graphs. Edges are reweighted with Bellman-Ford to get rid of negative weights, before running
+// Dijkstra from every node. If the Graph holds a negative cycle (any negative edge, in undirected graphs),
+// a *NegativeCycleError is returned.
+func Johnson(g *Graph) (*DistanceMatrix, error) {
+	x := g.Index()
+	n := x.Len()
+
+	// Bellman-Ford from a virtual source, connected to every node with a 0-weight edge
+	r, _, _ := newRelaxation(g, 0)
+	for i := 0; i < n; i++ {
+		r.dist[i], r.pred[i], r.reached[i] = 0, i, true
+	}
+	if err := r.run(); err != nil {
+		return nil, err
+	}
+	h := r.dist
+
+	m := newDistanceMatrix(x)
+	for s := 0; s < n; s++ {
+		dist, pred := m.dist[s], m.pred[s]
+		done := container.NewBitset(n)
+
+		// Reweighted edges w(u, v) + h[u] - h[v] are never negative
+		pq := &container.PQueue{}
+		pq.Push(s, 0)
+		for pq.Len() > 0 {
+			i := pq.Pop().(int)
+			if done.Test(i) {
+				continue
+			}
+			done.Set(i)
+			for _, j := range r.adj[i] {
+				if done.Test(j) {
+					continue
+				}
+				d := dist[i] + g.edges[x.ID(i)][x.ID(j)].Weight + h[i] - h[j]
+				if pred[j] < 0 || d < dist[j] {
+					dist[j], pred[j] = d, i
+					pq.Push(j, d)
+				}
+			}
+		}
+
+		// Restore the original weights
+		for j := 0; j < n; j++ {
+			if pred[j] >= 0 {
+				dist[j] += h[j] - h[s]
+			}
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,98 @@
+package grapho
+
+import (
+	"testing"
+)
+
+func TestAllPairs(t *testing.T) {
+	algorithms := map[string]func(*Graph) (*DistanceMatrix, error){
+		"FloydWarshall": FloydWarshall,
+		"Johnson":       Johnson,
+	}
+
+	for name, allPairs := range algorithms {
+		for _, g := range []*Graph{negativeGraph(), sampleGraph(), sampleDiGraph()} {
+			m, err := allPairs(g)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+
+			// Compare every distance with the shortest path tree of each node
+			for _, u := range g.Index().IDs() {
+				tree, err := ShortestPathTree(g, u, BellmanFord)
+				if err != nil {
+					t.Fatalf("ShortestPathTree(%d): %v", u, err)
+				}
+				for _, v := range g.Index().IDs() {
+					d, ok := m.Dist(u, v)
+					expected, reachable := tree.Dist[v]
+					if ok != reachable || d != expected {
+						t.Errorf("%s: Dist(%d, %d): %d, %v. Expected: %d, %v", name, u, v, d, ok, expected, reachable)
+					}
+				}
+			}
+		}
+
+		m, _ := allPairs(negativeGraph())
+		path, err := m.Path(1, 5)
+		if err != nil || !equalPath(path, []uint64{1, 2, 3, 4, 5}) {
+			t.Errorf("%s: Path(1, 5): %v, %v. Expected: [1 2 3 4 5]", name, path, err)
+		}
+		if path, err := m.Path(3, 3); err != nil || !equalPath(path, []uint64{3}) {
+			t.Errorf("%s: Path(3, 3): %v, %v. Expected: [3]", name, path, err)
+		}
+		if _, err := m.Path(5, 1); err == nil {
+			t.Errorf("%s: Path(5, 1): Did not get expected error", name)
+		}
+		if _, err := m.Path(1, 7); err == nil {
+			t.Errorf("%s: Path(1, 7): Did not get expected error", name)
+		}
+		if _, ok := m.Dist(7, 1); ok {
+			t.Errorf("%s: Dist(7, 1): Unexpected distance for a missing node", name)
+		}
+	}
+}
+
+func TestAllPairsNegativeCycle(t *testing.T) {
+	algorithms := map[string]func(*Graph) (*DistanceMatrix, error){
+		"FloydWarshall": FloydWarshall,
+		"Johnson":       Johnson,
+	}
+
+	// 1 -> 2 -> 3 -> 2, with a cycle cost of -1, not reachable from 4
+	g := NewGraph(true)
+	g.AddEdge(1, 2, 1, nil)
+	g.AddEdge(2, 3, 2, nil)
+	g.AddEdge(3, 2, -3, nil)
+	g.AddEdge(4, 1, 1, nil)
+
+	loop := NewGraph(true)
+	loop.AddEdge(1, 2, 1, nil)
+	loop.AddEdge(2, 2, -1, nil)
+
+	for name, allPairs := range algorithms {
+		_, err := allPairs(g)
+		cycleErr, ok := err.(*NegativeCycleError)
+		if !ok {
+			t.Errorf("%s: %v. Expected a NegativeCycleError", name, err)
+		} else if len(cycleErr.Cycle) != 2 {
+			t.Errorf("%s: cycle %v. Expected [2 3]", name, cycleErr.Cycle)
+		}
+
+		_, err = allPairs(loop)
+		if cycleErr, ok := err.(*NegativeCycleError); !ok || !equalPath(cycleErr.Cycle, []uint64{2}) {
+			t.Errorf("%s: %v. Expected a negative cycle on 2", name, err)
+		}
+
+		// A self-loop with a positive weight does not shorten any path
+		loop.AddEdge(2, 2, 1, nil)
+		m, err := allPairs(loop)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if d, ok := m.Dist(2, 2); !ok || d != 0 {
+			t.Errorf("%s: Dist(2, 2): %d, %v. Expected: 0", name, d, ok)
+		}
+		loop.AddEdge(2, 2, -1, nil)
+	}
+}
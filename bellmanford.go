@@ -0,0 +1,180 @@
+package grapho
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ichinaski/grapho/container"
+)
+
+// NegativeCycleError is returned when a cycle with negative total weight is found.
+// Cycle holds its nodes in order, the last one being connected to the first one.
+type NegativeCycleError struct {
+	Cycle []uint64
+}
+
+func (e *NegativeCycleError) Error() string {
+	nodes := make([]string, len(e.Cycle)+1)
+	for i, node := range e.Cycle {
+		nodes[i] = fmt.Sprint(node)
+	}
+	nodes[len(e.Cycle)] = nodes[0]
+	return "Negative cycle found: " + strings.Join(nodes, " -> ")
+}
+
+// relaxation holds the state of the Bellman-Ford and SPFA algorithms, with nodes as Index positions
+type relaxation struct {
+	graph   *Graph
+	x       *Index
+	adj     [][]int
+	dist    []int
+	pred    []int
+	reached []bool
+	stats   searchstats
+}
+
+// newRelaxation creates the relaxation state from start, returning its position,
+// and false if start is not in the Graph
+func newRelaxation(graph *Graph, start uint64) (*relaxation, int, bool) {
+	x := graph.Index()
+	r := &relaxation{
+		graph:   graph,
+		x:       x,
+		adj:     graph.adjacency(x),
+		dist:    make([]int, x.Len()),
+		pred:    make([]int, x.Len()),
+		reached: make([]bool, x.Len()),
+	}
+	s, ok := x.Pos(start)
+	if ok {
+		r.pred[s], r.reached[s] = s, true
+	}
+	return r, s, ok
+}
+
+// relax relaxes the edge between the nodes at positions i and j, returning whether dist[j] was improved
+func (r *relaxation) relax(i, j int) bool {
+	weight := r.graph.edges[r.x.ID(i)][r.x.ID(j)].Weight
+	if r.reached[j] && r.dist[i]+weight >= r.dist[j] {
+		return false
+	}
+	r.dist[j], r.pred[j], r.reached[j] = r.dist[i]+weight, i, true
+	return true
+}
+
+// result converts the reached nodes into a closed set, as returned by traverse
+func (r *relaxation) result() map[uint64]searchstate {
+	closedSet := make(map[uint64]searchstate)
+	for i, ok := range r.reached {
+		if ok {
+			node := r.x.ID(i)
+			closedSet[node] = searchstate{node, r.x.ID(r.pred[i]), r.dist[i]}
+		}
+	}
+	return closedSet
+}
+
+// cycle returns the negative cycle found through the predecessor of the node at position i,
+// which must have been improved after the distances have converged
+func (r *relaxation) cycle(i int) *NegativeCycleError {
+	// Walking back n times ensures we are inside the cycle
+	for k := 0; k < r.x.Len(); k++ {
+		i = r.pred[i]
+	}
+
+	cycle := []uint64{r.x.ID(i)}
+	for j := r.pred[i]; j != i; j = r.pred[j] {
+		cycle = append(cycle, r.x.ID(j))
+	}
+	// Reverse the slice, to follow the edges direction
+	for a, b := 0, len(cycle)-1; a < b; a, b = a+1, b-1 {
+		cycle[a], cycle[b] = cycle[b], cycle[a]
+	}
+	return &NegativeCycleError{cycle}
+}
+
+// bellmanFord finds the shortest paths from start to every reachable node, supporting negative weights.
+// The result is returned as traverse does, unless a negative cycle is reachable from start.
+func bellmanFord(graph *Graph, start uint64) (map[uint64]searchstate, searchstats, error) {
+	r, _, _ := newRelaxation(graph, start)
+	if err := r.run(); err != nil {
+		return nil, r.stats, err
+	}
+	return r.result(), r.stats, nil
+}
+
+// run relaxes every edge leaving a reached node until the distances converge, or returns
+// the negative cycle that keeps them from converging
+func (r *relaxation) run() error {
+	// pass relaxes every edge leaving a reached node, returning the last improved node, or -1
+	pass := func() int {
+		last := -1
+		for i, succ := range r.adj {
+			if !r.reached[i] {
+				continue
+			}
+			r.stats.expanded++
+			for _, j := range succ {
+				if r.relax(i, j) {
+					last = j
+				}
+			}
+		}
+		return last
+	}
+
+	for k := 0; k < r.x.Len()-1; k++ {
+		if pass() < 0 {
+			return nil
+		}
+	}
+	// Any improvement after n-1 passes comes from a negative cycle
+	if last := pass(); last >= 0 {
+		return r.cycle(last)
+	}
+	return nil
+}
+
+// spfa finds the shortest paths from start to every reachable node, as bellmanFord does, only relaxing
+// the edges of the nodes whose distance has been improved, kept in a queue.
+func spfa(graph *Graph, start uint64) (map[uint64]searchstate, searchstats, error) {
+	r, s, ok := newRelaxation(graph, start)
+	if !ok {
+		return r.result(), r.stats, nil
+	}
+
+	n := r.x.Len()
+	queue := container.NewQueue()
+	queued := container.NewBitset(n)
+	edges := make([]int, n) // number of edges in the path to every node
+
+	queue.Push(s)
+	queued.Set(s)
+	r.stats.maxOpenSet = 1
+	for queue.Len() > 0 {
+		i := queue.Pop().(int)
+		queued.Clear(i)
+		r.stats.expanded++
+
+		for _, j := range r.adj[i] {
+			if !r.relax(i, j) {
+				continue
+			}
+			edges[j] = edges[i] + 1
+			if edges[j] >= n {
+				// A path with n edges holds a cycle. Let Bellman-Ford find it
+				closedSet, stats, err := bellmanFord(graph, start)
+				r.stats.expanded += stats.expanded
+				return closedSet, r.stats, err
+			}
+			if !queued.Test(j) {
+				queue.Push(j)
+				queued.Set(j)
+				if queue.Len() > r.stats.maxOpenSet {
+					r.stats.maxOpenSet = queue.Len()
+				}
+			}
+		}
+	}
+	return r.result(), r.stats, nil
+}
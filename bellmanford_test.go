@@ -0,0 +1,164 @@
+package grapho
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// negativeGraph creates a directed Graph with negative weights, where Dijkstra fails to find the shortest path
+func negativeGraph() *Graph {
+	g := NewGraph(true)
+	g.AddEdge(1, 2, 4, nil)
+	g.AddEdge(1, 3, 2, nil)
+	g.AddEdge(2, 3, -3, nil)
+	g.AddEdge(3, 4, 2, nil)
+	g.AddEdge(2, 4, 3, nil)
+	g.AddEdge(4, 5, -1, nil)
+	g.AddNode(6, nil)
+	return g
+}
+
+func TestBellmanFord(t *testing.T) {
+	for _, algo := range []SearchAlgorithm{BellmanFord, SPFA} {
+		g := negativeGraph()
+
+		result, err := SearchPath(g, 1, 5, algo, nil)
+		if err != nil {
+			t.Fatalf("SearchPath(%v): %v", algo, err)
+		}
+		expected := []uint64{1, 2, 3, 4, 5}
+		if !equalPath(result.Path, expected) || result.Cost != 2 {
+			t.Errorf("SearchPath(%v): %v, cost %d. Expected: %v, cost 2", algo, result.Path, result.Cost, expected)
+		}
+		if result.Expanded == 0 {
+			t.Errorf("SearchPath(%v): no nodes expanded", algo)
+		}
+
+		if _, err := Search(g, 1, 6, algo, nil); err == nil {
+			t.Errorf("Search(%v): Did not get expected error", algo)
+		}
+
+		tree, err := ShortestPathTree(g, 1, algo)
+		if err != nil {
+			t.Fatalf("ShortestPathTree(%v): %v", algo, err)
+		}
+		dist := map[uint64]int{1: 0, 2: 4, 3: 1, 4: 3, 5: 2}
+		if len(tree.Dist) != len(dist) {
+			t.Errorf("Dist(%v): %v. Expected: %v", algo, tree.Dist, dist)
+		}
+		for node, d := range dist {
+			if tree.Dist[node] != d {
+				t.Errorf("Dist[%d](%v): %d. Expected: %d", node, algo, tree.Dist[node], d)
+			}
+		}
+	}
+}
+
+func TestBellmanFordNegativeCycle(t *testing.T) {
+	for _, algo := range []SearchAlgorithm{BellmanFord, SPFA} {
+		// 0 -> 1 -> 2 -> 3 -> 1, with a cycle cost of -1
+		g := NewGraph(true)
+		g.AddEdge(0, 1, 1, nil)
+		g.AddEdge(1, 2, 2, nil)
+		g.AddEdge(2, 3, -4, nil)
+		g.AddEdge(3, 1, 1, nil)
+		g.AddEdge(3, 4, 1, nil)
+		g.AddEdge(5, 0, 1, nil)
+
+		_, err := Search(g, 0, 4, algo, nil)
+		cycleErr, ok := err.(*NegativeCycleError)
+		if !ok {
+			t.Fatalf("Search(%v): %v. Expected a NegativeCycleError", algo, err)
+		}
+		if len(cycleErr.Cycle) != 3 {
+			t.Fatalf("Search(%v): cycle %v. Expected 3 nodes", algo, cycleErr.Cycle)
+		}
+		// The cycle may start at any of its nodes
+		for i, node := range cycleErr.Cycle {
+			next := cycleErr.Cycle[(i+1)%len(cycleErr.Cycle)]
+			if _, ok := g.Edge(node, next); !ok || node < 1 || node > 3 {
+				t.Errorf("Search(%v): invalid cycle %v", algo, cycleErr.Cycle)
+				break
+			}
+		}
+		if _, err := ShortestPathTree(g, 0, algo); err == nil {
+			t.Errorf("ShortestPathTree(%v): Did not get expected error", algo)
+		}
+
+		// The cycle is not reachable from 4
+		g.AddEdge(4, 6, 1, nil)
+		if path, err := Search(g, 4, 6, algo, nil); err != nil || !equalPath(path, []uint64{4, 6}) {
+			t.Errorf("Search(%v): %v, %v. Expected: [4 6]", algo, path, err)
+		}
+	}
+
+	err := &NegativeCycleError{[]uint64{1, 2, 3}}
+	if err.Error() != "Negative cycle found: 1 -> 2 -> 3 -> 1" {
+		t.Errorf("Unexpected error message: %q", err.Error())
+	}
+}
+
+// TestBellmanFordUndirected tests that a negative edge in an undirected Graph is a negative cycle
+func TestBellmanFordUndirected(t *testing.T) {
+	g := NewGraph(false)
+	g.AddEdge(1, 2, 1, nil)
+	g.AddEdge(2, 3, -1, nil)
+
+	for _, algo := range []SearchAlgorithm{BellmanFord, SPFA} {
+		_, err := Search(g, 1, 3, algo, nil)
+		if cycleErr, ok := err.(*NegativeCycleError); !ok || len(cycleErr.Cycle) != 2 {
+			t.Errorf("Search(%v): %v. Expected a negative cycle between 2 and 3", algo, err)
+		}
+	}
+
+	// Positive weights give the same results as Dijkstra
+	g = sampleGraph()
+	for _, algo := range []SearchAlgorithm{BellmanFord, SPFA} {
+		path, err := Search(g, 1, 8, algo, nil)
+		if err != nil || !equalPath(path, []uint64{1, 2, 5, 8}) {
+			t.Errorf("Search(%v): %v, %v. Expected: [1 2 5 8]", algo, path, err)
+		}
+	}
+}
+
+// TestBellmanFordMissingStart tests searches from nodes not present in the Graph
+func TestBellmanFordMissingStart(t *testing.T) {
+	for _, algo := range []SearchAlgorithm{BellmanFord, SPFA} {
+		for _, g := range []*Graph{NewGraph(true), negativeGraph()} {
+			if _, err := Search(g, 7, 2, algo, nil); err == nil || err.Error() != "Path not found" {
+				t.Errorf("Search(%v): %v. Expected: Path not found", algo, err)
+			}
+		}
+	}
+}
+
+// TestSPFARandom compares SPFA with Bellman-Ford on random graphs with negative weights
+func TestSPFARandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 1000; iter++ {
+		n := 2 + rnd.Intn(6)
+		g := NewGraph(true)
+		for i := 1; i <= n; i++ {
+			g.AddNode(uint64(i), nil)
+		}
+		for e := rnd.Intn(n * n); e > 0; e-- {
+			if u, v := uint64(1+rnd.Intn(n)), uint64(1+rnd.Intn(n)); u != v {
+				g.AddEdge(u, v, rnd.Intn(9)-3, nil)
+			}
+		}
+
+		expected, _, experr := bellmanFord(g, 1)
+		closedSet, _, err := spfa(g, 1)
+		if (err == nil) != (experr == nil) {
+			t.Fatalf("spfa: %v. Expected: %v", err, experr)
+		}
+		if len(closedSet) != len(expected) {
+			t.Fatalf("spfa: %d nodes reached. Expected: %d", len(closedSet), len(expected))
+		}
+		for node, state := range expected {
+			if closedSet[node].cost != state.cost {
+				t.Fatalf("spfa: cost %d to node %d. Expected: %d", closedSet[node].cost, node, state.cost)
+			}
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package grapho
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Binary encoding layout (version 1). Integers are varint encoded (zig-zag for signed values):
+//
+//	magic "GRPH" | version | flags (bit 0: directed) | node count
+//	for each node, in ascending order: id delta from the previous node | attributes
+//	for each node, in ascending order: edge count | for each edge, in ascending target order:
+//	    target delta from the previous target (or from 0) | weight | attributes
+//
+// In undirected graphs, edges are encoded once, from the lowest node.
+const (
+	binaryMagic   = "GRPH"
+	binaryVersion = 1
+)
+
+// AttrCodec encodes and decodes the attribute sets of nodes and edges in the binary encoding.
+type AttrCodec interface {
+	EncodeAttr(w *bufio.Writer, attr Attr) error
+	DecodeAttr(r *bufio.Reader) (Attr, error)
+}
+
+// DefaultAttrCodec is the AttrCodec used by MarshalBinary, UnmarshalBinary, WriteTo and ReadFrom.
+var DefaultAttrCodec AttrCodec = BasicAttrCodec{}
+
+// BinaryCodec encodes and decodes Graphs in the binary format, using a custom AttrCodec.
+// If Attr is nil, DefaultAttrCodec is used.
+type BinaryCodec struct {
+	Attr AttrCodec
+}
+
+// attrCodec returns the AttrCodec in use
+func (c BinaryCodec) attrCodec() AttrCodec {
+	if c.Attr == nil {
+		return DefaultAttrCodec
+	}
+	return c.Attr
+}
+
+// Value type tags used by BasicAttrCodec
+const (
+	tagBool byte = iota
+	tagInt
+	tagInt64
+	tagUint64
+	tagFloat32
+	tagFloat64
+	tagString
+	tagBytes
+)
+
+// BasicAttrCodec encodes attribute values of type bool, int, int64, uint64, float32, float64,
+// string and []byte. Any other type makes the encoding fail.
+type BasicAttrCodec struct{}
+
+// EncodeAttr implements the AttrCodec interface.
+func (BasicAttrCodec) EncodeAttr(w *bufio.Writer, attr Attr) error {
+	writeUvarint(w, uint64(len(attr)))
+	for _, k := range sortedKeys(attr) {
+		writeString(w, k)
+		switch v := attr[k].(type) {
+		case bool:
+			if v {
+				w.Write([]byte{tagBool, 1})
+			} else {
+				w.Write([]byte{tagBool, 0})
+			}
+		case int:
+			w.WriteByte(tagInt)
+			writeVarint(w, int64(v))
+		case int64:
+			w.WriteByte(tagInt64)
+			writeVarint(w, v)
+		case uint64:
+			w.WriteByte(tagUint64)
+			writeUvarint(w, v)
+		case float32:
+			w.WriteByte(tagFloat32)
+			writeFixed(w, uint64(math.Float32bits(v)), 4)
+		case float64:
+			w.WriteByte(tagFloat64)
+			writeFixed(w, math.Float64bits(v), 8)
+		case string:
+			w.WriteByte(tagString)
+			writeString(w, v)
+		case []byte:
+			w.WriteByte(tagBytes)
+			writeString(w, string(v))
+		default:
+			return fmt.Errorf("Unsupported type %T for attribute %q", v, k)
+		}
+	}
+	return nil
+}
+
+// DecodeAttr implements the AttrCodec interface.
+func (BasicAttrCodec) DecodeAttr(r *bufio.Reader) (Attr, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+
+	attr := NewAttr()
+	for i := uint64(0); i < n; i++ {
+		k, err := readString(r)
+		if err != nil {
+			return nil, err
+		}
+		tag, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		var v interface{}
+		switch tag {
+		case tagBool:
+			var b byte
+			b, err = r.ReadByte()
+			v = b != 0
+		case tagInt:
+			var x int64
+			x, err = binary.ReadVarint(r)
+			v = int(x)
+		case tagInt64:
+			v, err = binary.ReadVarint(r)
+		case tagUint64:
+			v, err = binary.ReadUvarint(r)
+		case tagFloat32:
+			var x uint64
+			x, err = readFixed(r, 4)
+			v = math.Float32frombits(uint32(x))
+		case tagFloat64:
+			var x uint64
+			x, err = readFixed(r, 8)
+			v = math.Float64frombits(x)
+		case tagString:
+			v, err = readString(r)
+		case tagBytes:
+			var s string
+			s, err = readString(r)
+			v = []byte(s)
+		default:
+			return nil, fmt.Errorf("Unknown type tag %d for attribute %q", tag, k)
+		}
+		if err != nil {
+			return nil, err
+		}
+		attr[k] = v
+	}
+	return attr, nil
+}
+
+// NoAttrCodec discards every attribute, encoding only the Graph structure and weights.
+// Decoded nodes and edges get empty attribute sets.
+type NoAttrCodec struct{}
+
+// EncodeAttr implements the AttrCodec interface.
+func (NoAttrCodec) EncodeAttr(w *bufio.Writer, attr Attr) error { return nil }
+
+// DecodeAttr implements the AttrCodec interface.
+func (NoAttrCodec) DecodeAttr(r *bufio.Reader) (Attr, error) { return NewAttr(), nil }
+
+// Encode writes the Graph to w in the binary format.
+func (c BinaryCodec) Encode(w io.Writer, g *Graph) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	codec := c.attrCodec()
+
+	bw.WriteString(binaryMagic)
+	bw.WriteByte(binaryVersion)
+	var flags byte
+	if g.directed {
+		flags |= 1
+	}
+	bw.WriteByte(flags)
+
+	nodes := g.sortedNodeIDs()
+	writeUvarint(bw, uint64(len(nodes)))
+	var prev uint64
+	for _, node := range nodes {
+		writeUvarint(bw, node-prev)
+		prev = node
+		if err := codec.EncodeAttr(bw, g.nodes[node]); err != nil {
+			return cw.n, err
+		}
+	}
+
+	succ := make([]uint64, 0)
+	for _, u := range nodes {
+		succ = succ[:0]
+		for v := range g.edges[u] {
+			if g.directed || u <= v {
+				succ = append(succ, v)
+			}
+		}
+		sort.Sort(uint64Slice(succ))
+
+		writeUvarint(bw, uint64(len(succ)))
+		prev = 0
+		for _, v := range succ {
+			edge := g.edges[u][v]
+			writeUvarint(bw, v-prev)
+			prev = v
+			writeVarint(bw, int64(edge.Weight))
+			if err := codec.EncodeAttr(bw, edge.Attr); err != nil {
+				return cw.n, err
+			}
+		}
+	}
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// Decode reads a Graph in the binary format from r, returning the number of bytes read.
+// r is never consumed past the end of the encoded Graph: seekable readers (i.e. files) are read
+// in blocks, seeking back any bytes read past it, io.ByteReaders (i.e. a bufio.Reader) are read
+// with ReadByte, and any other reader one byte at a time, with a Read call per byte.
+func (c BinaryCodec) Decode(r io.Reader) (*Graph, int64, error) {
+	cr := &countingReader{r: r}
+	seeker, seekable := r.(io.Seeker)
+	if seekable {
+		_, err := seeker.Seek(0, io.SeekCurrent) // i.e. pipes cannot seek
+		seekable = err == nil
+	}
+
+	var br *bufio.Reader
+	if seekable {
+		br = bufio.NewReader(cr)
+	} else {
+		// Reading one byte at a time keeps the bufio.Reader from buffering past the encoded Graph
+		br = bufio.NewReader(byteReader{cr})
+	}
+
+	g, err := c.decode(br)
+	n := cr.n - int64(br.Buffered())
+	if seekable && br.Buffered() > 0 {
+		if _, serr := seeker.Seek(-int64(br.Buffered()), io.SeekCurrent); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return g, n, err
+}
+
+func (c BinaryCodec) decode(r *bufio.Reader) (*Graph, error) {
+	codec := c.attrCodec()
+	header := make([]byte, len(binaryMagic)+2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if string(header[:len(binaryMagic)]) != binaryMagic {
+		return nil, errors.New("Invalid binary encoding")
+	}
+	if version := header[len(binaryMagic)]; version != binaryVersion {
+		return nil, fmt.Errorf("Unsupported binary encoding version %d", version)
+	}
+
+	g := NewGraph(header[len(binaryMagic)+1]&1 != 0)
+
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	size := count
+	if size > 1<<20 { // do not trust the count for large preallocations
+		size = 1 << 20
+	}
+	nodes := make([]uint64, 0, size)
+	var prev uint64
+	for i := uint64(0); i < count; i++ {
+		delta, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		node := prev + delta
+		prev = node
+
+		attr, err := codec.DecodeAttr(r)
+		if err != nil {
+			return nil, err
+		}
+		g.AddNode(node, attr)
+		nodes = append(nodes, node)
+	}
+
+	for _, u := range nodes {
+		count, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		if g.directed && count > 0 && count <= uint64(len(nodes)) {
+			g.edges[u] = make(map[uint64]*Edge, count) // presize the adjacency list
+		}
+		prev = 0
+		for i := uint64(0); i < count; i++ {
+			delta, err := binary.ReadUvarint(r)
+			if err != nil {
+				return nil, err
+			}
+			v := prev + delta
+			prev = v
+
+			weight, err := binary.ReadVarint(r)
+			if err != nil {
+				return nil, err
+			}
+			attr, err := codec.DecodeAttr(r)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := g.nodes[v]; !ok {
+				return nil, fmt.Errorf("Edge %d-%d references an unknown node", u, v)
+			}
+			g.AddEdge(u, v, int(weight), attr)
+		}
+	}
+
+	return g, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface, using DefaultAttrCodec.
+func (g *Graph) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	_, err := BinaryCodec{DefaultAttrCodec}.Encode(&buf, g)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface, using DefaultAttrCodec.
+// Any previous content of the Graph is discarded.
+func (g *Graph) UnmarshalBinary(data []byte) error {
+	decoded, _, err := BinaryCodec{DefaultAttrCodec}.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	*g = *decoded
+	return nil
+}
+
+// WriteTo implements the io.WriterTo interface, writing the binary encoding of the Graph.
+func (g *Graph) WriteTo(w io.Writer) (int64, error) {
+	return BinaryCodec{DefaultAttrCodec}.Encode(w, g)
+}
+
+// ReadFrom implements the io.ReaderFrom interface, reading a binary encoded Graph.
+// Any previous content of the Graph is discarded. r is not consumed past the end of
+// the encoded Graph (see BinaryCodec.Decode for the performance of each kind of reader).
+func (g *Graph) ReadFrom(r io.Reader) (int64, error) {
+	decoded, n, err := BinaryCodec{DefaultAttrCodec}.Decode(r)
+	if err != nil {
+		return n, err
+	}
+	*g = *decoded
+	return n, nil
+}
+
+func writeUvarint(w *bufio.Writer, x uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(buf[:binary.PutUvarint(buf[:], x)])
+}
+
+func writeVarint(w *bufio.Writer, x int64) {
+	var buf [binary.MaxVarintLen64]byte
+	w.Write(buf[:binary.PutVarint(buf[:], x)])
+}
+
+// writeFixed writes the n lowest bytes of x, in little endian order.
+func writeFixed(w *bufio.Writer, x uint64, n int) {
+	for i := 0; i < n; i++ {
+		w.WriteByte(byte(x >> uint(8*i)))
+	}
+}
+
+// readFixed reads an n bytes little endian integer.
+func readFixed(r *bufio.Reader, n int) (uint64, error) {
+	var x uint64
+	for i := 0; i < n; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		x |= uint64(b) << uint(8*i)
+	}
+	return x, nil
+}
+
+func writeString(w *bufio.Writer, s string) {
+	writeUvarint(w, uint64(len(s)))
+	w.WriteString(s)
+}
+
+func readString(r *bufio.Reader) (string, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return "", err
+	}
+	if n > math.MaxInt32 {
+		return "", errors.New("Invalid string length")
+	}
+	if n <= 1<<20 {
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		return string(buf), nil
+	}
+
+	// do not trust the length for large preallocations: grow the buffer as data is read
+	var buf bytes.Buffer
+	buf.Grow(1 << 20)
+	if _, err := io.CopyN(&buf, r, int64(n)); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// byteReader reads at most one byte at a time from the underlying countingReader,
+// with ReadByte if its reader implements io.ByteReader.
+type byteReader struct {
+	cr *countingReader
+}
+
+func (b byteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if br, ok := b.cr.r.(io.ByteReader); ok {
+		c, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		p[0] = c
+		b.cr.n++
+		return 1, nil
+	}
+	return b.cr.Read(p[:1])
+}
@@ -0,0 +1,207 @@
+package grapho
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGraphBinary(t *testing.T) {
+	g := NewGraph(false)
+	g.AddNode(1<<40, Attr{"name": "Bob", "admin": true, "score": 1.5, "x": float32(2), "id": uint64(7)})
+	g.AddEdge(1<<40, 3, -5, Attr{"n": 3, "m": int64(-4), "raw": []byte("abc")})
+	g.AddEdge(3, 2, 1, nil)
+
+	data, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	var read Graph
+	if err := read.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if read.IsDirected() || read.Len() != 3 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", read.IsDirected(), read.Len())
+	}
+
+	attr, _ := read.Node(1 << 40)
+	if attr["name"] != "Bob" || attr["admin"] != true || attr["score"] != 1.5 || attr["x"] != float32(2) || attr["id"] != uint64(7) {
+		t.Errorf("Unexpected attributes for node 1<<40: %v", attr)
+	}
+	edge, ok := read.Edge(3, 1<<40)
+	if !ok || edge.Weight != -5 || edge.Attr["n"] != 3 || edge.Attr["m"] != int64(-4) || string(edge.Attr["raw"].([]byte)) != "abc" {
+		t.Errorf("Unexpected edge 3-1<<40: %v", edge)
+	}
+	testEdgeExists(t, &read, 2, 3, true)
+	if v := read.Validate(); len(v) != 0 {
+		t.Errorf("Unexpected violations: %v", v)
+	}
+
+	if err := read.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("UnmarshalBinary: Did not get expected error with truncated data")
+	}
+
+	g.AddNode(4, Attr{"unsupported": []int{1}})
+	if _, err := g.MarshalBinary(); err == nil {
+		t.Errorf("MarshalBinary: Did not get expected error with unsupported attribute")
+	}
+}
+
+func TestGraphWriteTo(t *testing.T) {
+	var buf bytes.Buffer
+	g := sampleDiGraph()
+	n, err := g.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo: %d bytes written. Expected %d", n, buf.Len())
+	}
+
+	buf.WriteString("trailing data")
+	var read Graph
+	m, err := read.ReadFrom(&buf)
+	if err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	if m != n {
+		t.Errorf("ReadFrom: %d bytes read. Expected %d", m, n)
+	}
+	if !read.IsDirected() || read.Len() != 9 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", read.IsDirected(), read.Len())
+	}
+	if buf.String() != "trailing data" {
+		t.Errorf("ReadFrom: consumed past the encoded graph, %q left", buf.String())
+	}
+	for _, e := range g.sortedEdges() {
+		testEdgeExists(t, &read, e.u, e.v, true)
+	}
+}
+
+func TestBinaryCodec(t *testing.T) {
+	g := NewGraph(true)
+	g.AddNode(1, Attr{"f": func() {}})
+	g.AddEdge(1, 2, 3, Attr{"ch": make(chan int)})
+
+	var buf bytes.Buffer
+	codec := BinaryCodec{NoAttrCodec{}}
+	if _, err := codec.Encode(&buf, g); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	read, _, err := codec.Decode(bufio.NewReader(&buf))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if edge, ok := read.Edge(1, 2); !ok || edge.Weight != 3 || len(edge.Attr) != 0 {
+		t.Errorf("Unexpected edge 1-2: %v", edge)
+	}
+}
+
+func TestBinaryCodecZeroValue(t *testing.T) {
+	g := NewGraph(false)
+	g.AddEdge(1, 2, 3, Attr{"name": "a"})
+
+	var buf bytes.Buffer
+	var codec BinaryCodec
+	if _, err := codec.Encode(&buf, g); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	read, _, err := codec.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if edge, ok := read.Edge(2, 1); !ok || edge.Weight != 3 || edge.Attr["name"] != "a" {
+		t.Errorf("Unexpected edge 1-2: %v", edge)
+	}
+}
+
+// TestGraphReadFromReaders tests that no kind of reader is consumed past the encoded Graph
+func TestGraphReadFromReaders(t *testing.T) {
+	data, err := sampleDiGraph().MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	data = append(data, "trailing data"...)
+	size := int64(len(data) - len("trailing data"))
+
+	f, err := ioutil.TempFile("", "grapho")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if _, err := f.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	f.Seek(0, io.SeekStart)
+
+	for name, r := range map[string]io.Reader{
+		"file":   f,                                      // seekable
+		"bufio":  bufio.NewReader(bytes.NewReader(data)), // io.ByteReader
+		"reader": io.MultiReader(bytes.NewReader(data)),  // neither
+		"bytes":  bytes.NewReader(data),                  // both
+	} {
+		var g Graph
+		n, err := g.ReadFrom(r)
+		if err != nil {
+			t.Errorf("%s: ReadFrom: %v", name, err)
+			continue
+		}
+		if n != size || g.Len() != 9 {
+			t.Errorf("%s: ReadFrom: %d bytes read, %d nodes. Expected %d bytes, 9 nodes", name, n, g.Len(), size)
+		}
+		if rest, _ := ioutil.ReadAll(r); string(rest) != "trailing data" {
+			t.Errorf("%s: ReadFrom: consumed past the encoded graph, %q left", name, rest)
+		}
+	}
+}
+
+func TestBinaryLongString(t *testing.T) {
+	// A string length of 2^31-1, with no data: the decoder must fail without allocating it
+	var buf bytes.Buffer
+	buf.WriteString(binaryMagic)
+	buf.Write([]byte{binaryVersion, 0, 1, 1, 1, 0xff, 0xff, 0xff, 0xff, 0x07})
+	if _, _, err := (BinaryCodec{}).Decode(&buf); err != io.ErrUnexpectedEOF {
+		t.Errorf("Decode: %v. Expected %v", err, io.ErrUnexpectedEOF)
+	}
+
+	g := NewGraph(true)
+	g.AddNode(1, Attr{"s": strings.Repeat("x", 3<<20)})
+	data, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	var read Graph
+	if err := read.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	if attr, _ := read.Node(1); attr["s"] != strings.Repeat("x", 3<<20) {
+		t.Errorf("Unexpected long string attribute")
+	}
+}
+
+func BenchmarkGraphReadFrom(b *testing.B) {
+	f, err := ioutil.TempFile("", "grapho")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if _, err := largeGraph(100000).WriteTo(f); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Seek(0, io.SeekStart)
+		var g Graph
+		if _, err := g.ReadFrom(f); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
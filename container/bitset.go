@@ -0,0 +1,43 @@
+package container
+
+// Bitset implements a fixed size set of non-negative integers, backed by a slice of words
+type Bitset struct {
+	words []uint64
+	size  int
+}
+
+// NewBitset creates an empty bitset, able to hold integers in the range [0, size)
+func NewBitset(size int) *Bitset {
+	return &Bitset{make([]uint64, (size+63)/64), size}
+}
+
+// Len returns the capacity of the bitset
+func (b *Bitset) Len() int {
+	return b.size
+}
+
+// Set adds i to the set
+func (b *Bitset) Set(i int) {
+	b.words[i/64] |= 1 << uint(i%64)
+}
+
+// Clear removes i from the set
+func (b *Bitset) Clear(i int) {
+	b.words[i/64] &^= 1 << uint(i%64)
+}
+
+// Test returns whether i belongs to the set
+func (b *Bitset) Test(i int) bool {
+	return b.words[i/64]&(1<<uint(i%64)) != 0
+}
+
+// Count returns the number of integers in the set
+func (b *Bitset) Count() int {
+	n := 0
+	for _, w := range b.words {
+		for ; w != 0; w &= w - 1 {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,204 @@
+// Cytoscape.js JSON format
+// https://js.cytoscape.org/#notation/elements-json
+
+package grapho
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// CytoscapeWeightKey is the edge data field holding Edge.Weight.
+const CytoscapeWeightKey = "weight"
+
+// maxCytoscapeWeight is the largest integer a JSON number, read as float64, holds exactly.
+const maxCytoscapeWeight = 1 << 53
+
+// cytoscapeDoc is the Cytoscape.js representation of a Graph, as exported by cy.json().
+// The "directed" field is not part of Cytoscape.js, but it is understood by other tools (i.e. NetworkX).
+type cytoscapeDoc struct {
+	Data     map[string]interface{} `json:"data"`
+	Directed bool                   `json:"directed"`
+	Elements json.RawMessage        `json:"elements"`
+}
+
+type cytoscapeElements struct {
+	Nodes []cytoscapeElement `json:"nodes"`
+	Edges []cytoscapeElement `json:"edges"`
+}
+
+type cytoscapeElement struct {
+	Group string                 `json:"group,omitempty"`
+	Data  map[string]interface{} `json:"data"`
+}
+
+// WriteCytoscapeJSON writes the Graph in Cytoscape.js JSON format, with the elements grouped in nodes and edges:
+//
+//	{"data": {}, "directed": true, "elements": {"nodes": [{"data": {"id": "1", ...}}], "edges": [{"data": {"id": "e0", "source": "1", "target": "2", "weight": 1, ...}}]}}
+//
+// Node and edge Attr values are written to their data field, thus they cannot use the reserved "id", "source",
+// "target" and CytoscapeWeightKey keys. Nodes and edges are ordered by node id, so the output is deterministic.
+// In undirected graphs, every edge is written once. Edge weights must not exceed 2^53 in absolute value, as
+// JavaScript numbers cannot hold larger integers exactly.
+func WriteCytoscapeJSON(w io.Writer, g *Graph) error {
+	data := func(attr Attr, reserved ...string) (map[string]interface{}, error) {
+		d := make(map[string]interface{}, len(attr)+len(reserved))
+		for k, v := range attr {
+			if containsString(reserved, k) {
+				return nil, fmt.Errorf("Attribute %q clashes with a reserved data field", k)
+			}
+			d[k] = v
+		}
+		return d, nil
+	}
+
+	elements := cytoscapeElements{Nodes: []cytoscapeElement{}, Edges: []cytoscapeElement{}}
+	for _, node := range g.sortedNodeIDs() {
+		d, err := data(g.nodes[node], "id")
+		if err != nil {
+			return fmt.Errorf("Node %d: %v", node, err)
+		}
+		d["id"] = strconv.FormatUint(node, 10)
+		elements.Nodes = append(elements.Nodes, cytoscapeElement{Data: d})
+	}
+	for i, edge := range g.sortedEdges() {
+		d, err := data(edge.Attr, "id", "source", "target", CytoscapeWeightKey)
+		if err != nil {
+			return fmt.Errorf("Edge %d-%d: %v", edge.u, edge.v, err)
+		}
+		if w := int64(edge.Weight); w > maxCytoscapeWeight || w < -maxCytoscapeWeight {
+			return fmt.Errorf("Edge %d-%d: weight %d out of range", edge.u, edge.v, edge.Weight)
+		}
+		d["id"] = fmt.Sprintf("e%d", i)
+		d["source"] = strconv.FormatUint(edge.u, 10)
+		d["target"] = strconv.FormatUint(edge.v, 10)
+		d[CytoscapeWeightKey] = edge.Weight
+		elements.Edges = append(elements.Edges, cytoscapeElement{Data: d})
+	}
+
+	raw, err := json.Marshal(elements)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(cytoscapeDoc{
+		Data:     map[string]interface{}{},
+		Directed: g.directed,
+		Elements: raw,
+	})
+}
+
+// ReadCytoscapeJSON builds a Graph from a Cytoscape.js JSON document, whose elements are either grouped
+// in nodes and edges (as written by WriteCytoscapeJSON) or given as a flat array, where edges are told apart
+// by their "group" (or, if missing, by their "source" field). The graph is directed if the top level
+// "directed" field is true. The data fields of every element, other than "id", "source", "target" and
+// CytoscapeWeightKey, are stored in Attr, following the encoding/json rules for interface{} values.
+// CytoscapeWeightKey, which must be an integer of up to 2^53 in absolute value, is read as Edge.Weight instead. Edges without it are given a weight of 1.
+// Edges whose endpoints were not declared as nodes create them.
+// Node ids are bound as in ReadDOT. The returned map binds each Cytoscape.js node id to its node id.
+func ReadCytoscapeJSON(r io.Reader) (*Graph, map[string]uint64, error) {
+	var doc cytoscapeDoc
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+
+	if len(doc.Elements) == 0 {
+		return nil, nil, errors.New("Missing elements")
+	}
+
+	var elements cytoscapeElements
+	if doc.Elements[0] == '[' {
+		var list []cytoscapeElement
+		if err := json.Unmarshal(doc.Elements, &list); err != nil {
+			return nil, nil, err
+		}
+		for _, e := range list {
+			_, edge := e.Data["source"]
+			switch {
+			case e.Group == "edges" || e.Group == "" && edge:
+				elements.Edges = append(elements.Edges, e)
+			case e.Group == "nodes" || e.Group == "":
+				elements.Nodes = append(elements.Nodes, e)
+			default:
+				return nil, nil, fmt.Errorf("Invalid element group %q", e.Group)
+			}
+		}
+	} else {
+		if err := json.Unmarshal(doc.Elements, &elements); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// field returns the given data field as a string, accepting integer numbers too
+	field := func(data map[string]interface{}, key string) (string, error) {
+		switch v := data[key].(type) {
+		case string:
+			return v, nil
+		case float64:
+			if v >= 0 && v == math.Trunc(v) {
+				return strconv.FormatFloat(v, 'f', -1, 64), nil
+			}
+		case nil:
+			return "", fmt.Errorf("Missing %q data field", key)
+		}
+		return "", fmt.Errorf("Invalid %q data field: %v", key, data[key])
+	}
+
+	var names []string
+	nodes := make([]string, len(elements.Nodes))
+	for i, e := range elements.Nodes {
+		id, err := field(e.Data, "id")
+		if err != nil {
+			return nil, nil, fmt.Errorf("Node %d: %v", i, err)
+		}
+		nodes[i] = id
+		names = append(names, id)
+	}
+	edges := make([][2]string, len(elements.Edges))
+	for i, e := range elements.Edges {
+		source, err := field(e.Data, "source")
+		if err != nil {
+			return nil, nil, fmt.Errorf("Edge %d: %v", i, err)
+		}
+		target, err := field(e.Data, "target")
+		if err != nil {
+			return nil, nil, fmt.Errorf("Edge %d: %v", i, err)
+		}
+		edges[i] = [2]string{source, target}
+		names = append(names, source, target)
+	}
+	ids := assignIDs(names)
+
+	g := NewGraph(doc.Directed)
+	for i, e := range elements.Nodes {
+		attr := NewAttr()
+		for k, v := range e.Data {
+			if k != "id" {
+				attr[k] = v
+			}
+		}
+		g.AddNode(ids[nodes[i]], attr)
+	}
+	for i, e := range elements.Edges {
+		weight := 1
+		if v, ok := e.Data[CytoscapeWeightKey]; ok {
+			f, ok := v.(float64)
+			if !ok || f != math.Trunc(f) || math.Abs(f) > maxCytoscapeWeight || float64(int(f)) != f {
+				return nil, nil, fmt.Errorf("Edge %d: invalid weight %v", i, v)
+			}
+			weight = int(f)
+		}
+
+		attr := NewAttr()
+		for k, v := range e.Data {
+			if k != "id" && k != "source" && k != "target" && k != CytoscapeWeightKey {
+				attr[k] = v
+			}
+		}
+		g.AddEdge(ids[edges[i][0]], ids[edges[i][1]], weight, attr)
+	}
+	return g, ids, nil
+}
@@ -0,0 +1,109 @@
+package grapho
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteCytoscapeJSON(t *testing.T) {
+	g := NewGraph(false)
+	g.AddNode(1, Attr{"name": "A"})
+	g.AddEdge(2, 1, 4, Attr{"kind": "road"})
+
+	var buf bytes.Buffer
+	if err := WriteCytoscapeJSON(&buf, g); err != nil {
+		t.Fatalf("WriteCytoscapeJSON: %v", err)
+	}
+	expected := `{"data":{},"directed":false,"elements":{"nodes":[{"data":{"id":"1","name":"A"}},{"data":{"id":"2"}}],` +
+		`"edges":[{"data":{"id":"e0","kind":"road","source":"1","target":"2","weight":4}}]}}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+
+	h, ids, err := ReadCytoscapeJSON(&buf)
+	if err != nil {
+		t.Fatalf("ReadCytoscapeJSON: %v", err)
+	}
+	if h.IsDirected() || h.Len() != 2 || ids["2"] != 2 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes, ids %v", h.IsDirected(), h.Len(), ids)
+	}
+	if attr, _ := h.Node(1); attr["name"] != "A" {
+		t.Errorf("Unexpected attributes for node 1: %v", attr)
+	}
+	if edge, ok := h.Edge(2, 1); !ok || edge.Weight != 4 || edge.Attr["kind"] != "road" || len(edge.Attr) != 1 {
+		t.Errorf("Unexpected edge 2-1: %v", edge)
+	}
+
+	g.AddNode(3, Attr{"id": "x"})
+	if err := WriteCytoscapeJSON(&buf, g); err == nil {
+		t.Error("Expected error with a reserved attribute")
+	}
+
+	g = NewGraph(true)
+	g.AddEdge(1, 2, 3000000000, nil)
+	g.AddEdge(2, 1, -1<<53, nil)
+	buf.Reset()
+	if err := WriteCytoscapeJSON(&buf, g); err != nil {
+		t.Fatalf("WriteCytoscapeJSON: %v", err)
+	}
+	if h, _, err = ReadCytoscapeJSON(&buf); err != nil {
+		t.Fatalf("ReadCytoscapeJSON: %v", err)
+	}
+	for _, e := range []struct {
+		u, v   uint64
+		weight int
+	}{
+		{1, 2, 3000000000}, {2, 1, -1 << 53},
+	} {
+		if edge, ok := h.Edge(e.u, e.v); !ok || edge.Weight != e.weight {
+			t.Errorf("Unexpected edge %d-%d: %v. Expected weight %d", e.u, e.v, edge, e.weight)
+		}
+	}
+
+	g.AddEdge(1, 3, 1<<53+1, nil)
+	if err := WriteCytoscapeJSON(&buf, g); err == nil {
+		t.Error("Expected error with a weight out of range")
+	}
+}
+
+func TestReadCytoscapeJSON(t *testing.T) {
+	src := `{"directed": true, "elements": [
+		{"group": "nodes", "data": {"id": "a", "label": "A"}},
+		{"data": {"id": "b"}},
+		{"data": {"id": "ab", "source": "a", "target": "b"}},
+		{"group": "edges", "data": {"source": "b", "target": "c", "weight": 2, "score": 0.5}}
+	]}`
+	g, ids, err := ReadCytoscapeJSON(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadCytoscapeJSON: %v", err)
+	}
+	if !g.IsDirected() || g.Len() != 3 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", g.IsDirected(), g.Len())
+	}
+	if ids["a"] != 1 || ids["b"] != 2 || ids["c"] != 3 {
+		t.Errorf("Unexpected ids: %v", ids)
+	}
+	if attr, _ := g.Node(1); attr["label"] != "A" {
+		t.Errorf("Unexpected attributes for node a: %v", attr)
+	}
+	if edge, ok := g.Edge(1, 2); !ok || edge.Weight != 1 {
+		t.Errorf("Unexpected edge a-b: %v", edge)
+	}
+	if edge, ok := g.Edge(2, 3); !ok || edge.Weight != 2 || edge.Attr["score"] != 0.5 {
+		t.Errorf("Unexpected edge b-c: %v", edge)
+	}
+	testEdgeExists(t, g, 2, 1, false)
+
+	for _, src := range []string{
+		`{}`,
+		`{"elements": [{"group": "other", "data": {"id": "a"}}]}`,
+		`{"elements": {"nodes": [{"data": {}}]}}`,
+		`{"elements": {"edges": [{"data": {"source": "a", "target": "b", "weight": 1.5}}]}}`,
+		`{"elements": {"edges": [{"data": {"source": "a", "target": "b", "weight": 1e16}}]}}`,
+	} {
+		if _, _, err := ReadCytoscapeJSON(strings.NewReader(src)); err == nil {
+			t.Errorf("Expected error reading %s", src)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Mermaid and PlantUML diagrams
+// https://mermaid.js.org/syntax/flowchart.html
+// https://plantuml.com/deployment-diagram
+
+package grapho
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DiagramOptions configures how a Graph is written as a Mermaid or PlantUML diagram.
+type DiagramOptions struct {
+	Label     string   // Node Attr key holding the node label. Nodes without it are labelled with their id
+	Cluster   string   // Node Attr key to group nodes by, in subgraphs (Mermaid) or packages (PlantUML). Omitted if empty
+	Weight    bool     // Whether to label edges with Edge.Weight
+	Direction string   // Layout direction: "TB", "BT", "LR" or "RL". Defaults to "TB". PlantUML only tells TB and LR apart
+	Path      []uint64 // Path to highlight, as returned by Search
+	Color     string   // Color of highlighted nodes and edges. Defaults to "red"
+	Keyword   string   // Mermaid diagram keyword: "flowchart" or "graph". Defaults to "flowchart"
+}
+
+// diagram holds the contents of a Graph to be written as a diagram, shared by the Mermaid and PlantUML writers.
+type diagram struct {
+	clusters  []string            // cluster names, sorted
+	members   map[string][]uint64 // nodes of each cluster (nodes out of any cluster are bound to "")
+	edges     []edgeRef
+	nodes     map[uint64]bool    // highlighted nodes
+	highlight map[[2]uint64]bool // highlighted edges
+	color     string
+}
+
+func newDiagram(g *Graph, opts *DiagramOptions) *diagram {
+	d := &diagram{
+		members:   make(map[string][]uint64),
+		edges:     g.sortedEdges(),
+		nodes:     make(map[uint64]bool),
+		highlight: make(map[[2]uint64]bool),
+		color:     opts.Color,
+	}
+	if d.color == "" {
+		d.color = "red"
+	}
+
+	for _, node := range g.sortedNodeIDs() {
+		cluster := ""
+		if opts.Cluster != "" {
+			if v, ok := g.nodes[node][opts.Cluster]; ok {
+				cluster = fmt.Sprint(v)
+			}
+		}
+		if _, ok := d.members[cluster]; !ok && cluster != "" {
+			d.clusters = append(d.clusters, cluster)
+		}
+		d.members[cluster] = append(d.members[cluster], node)
+	}
+	sort.Strings(d.clusters)
+
+	for i, node := range opts.Path {
+		d.nodes[node] = true
+		if i > 0 {
+			u, v := opts.Path[i-1], node
+			if !g.directed && u > v {
+				u, v = v, u
+			}
+			d.highlight[[2]uint64{u, v}] = true
+		}
+	}
+	return d
+}
+
+// diagramLabel returns the label of the node, as given by its opts.Label attribute.
+func diagramLabel(g *Graph, node uint64, opts *DiagramOptions) string {
+	if opts.Label != "" {
+		if v, ok := g.nodes[node][opts.Label]; ok {
+			return fmt.Sprint(v)
+		}
+	}
+	return fmt.Sprint(node)
+}
+
+// WriteMermaid writes the Graph as a Mermaid flowchart. Nodes are named "n<id>", and written in ascending
+// node order, grouped in subgraphs by their opts.Cluster attribute. Highlighted nodes are bound to
+// the "highlight" class, and highlighted edges styled with linkStyle.
+func WriteMermaid(w io.Writer, g *Graph, opts *DiagramOptions) error {
+	if opts == nil {
+		opts = &DiagramOptions{}
+	}
+	keyword := opts.Keyword
+	if keyword == "" {
+		keyword = "flowchart"
+	}
+	if keyword != "flowchart" && keyword != "graph" {
+		return fmt.Errorf("Invalid Mermaid keyword %q", keyword)
+	}
+	direction, err := diagramDirection(opts.Direction)
+	if err != nil {
+		return err
+	}
+	d := newDiagram(g, opts)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s %s\n", keyword, direction)
+
+	writeNodes := func(nodes []uint64, indent string) {
+		for _, node := range nodes {
+			fmt.Fprintf(bw, "%sn%d[\"%s\"]\n", indent, node, mermaidEscape(diagramLabel(g, node, opts)))
+		}
+	}
+	for i, cluster := range d.clusters {
+		fmt.Fprintf(bw, "    subgraph cluster%d [\"%s\"]\n", i, mermaidEscape(cluster))
+		writeNodes(d.members[cluster], "        ")
+		bw.WriteString("    end\n")
+	}
+	writeNodes(d.members[""], "    ")
+
+	op := "---"
+	if g.directed {
+		op = "-->"
+	}
+	var links []string
+	for i, edge := range d.edges {
+		label := ""
+		if opts.Weight {
+			label = fmt.Sprintf("|%d|", edge.Weight)
+		}
+		fmt.Fprintf(bw, "    n%d %s%s n%d\n", edge.u, op, label, edge.v)
+		if d.highlight[[2]uint64{edge.u, edge.v}] {
+			links = append(links, fmt.Sprint(i))
+		}
+	}
+
+	if len(d.nodes) > 0 {
+		var names []string
+		for _, node := range g.sortedNodeIDs() {
+			if d.nodes[node] {
+				names = append(names, fmt.Sprintf("n%d", node))
+			}
+		}
+		fmt.Fprintf(bw, "    classDef highlight stroke:%s,stroke-width:2px\n", d.color)
+		fmt.Fprintf(bw, "    class %s highlight\n", strings.Join(names, ","))
+	}
+	if len(links) > 0 {
+		fmt.Fprintf(bw, "    linkStyle %s stroke:%s,stroke-width:2px\n", strings.Join(links, ","), d.color)
+	}
+	return bw.Flush()
+}
+
+// WritePlantUML writes the Graph as a PlantUML diagram, with a rectangle for every node, named "n<id>"
+// and written in ascending node order, grouped in packages by their opts.Cluster attribute.
+// Double quotes in labels are replaced by single quotes.
+func WritePlantUML(w io.Writer, g *Graph, opts *DiagramOptions) error {
+	if opts == nil {
+		opts = &DiagramOptions{}
+	}
+	direction, err := diagramDirection(opts.Direction)
+	if err != nil {
+		return err
+	}
+	d := newDiagram(g, opts)
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("@startuml\n")
+	if direction == "LR" || direction == "RL" {
+		bw.WriteString("left to right direction\n")
+	}
+
+	writeNodes := func(nodes []uint64, indent string) {
+		for _, node := range nodes {
+			style := ""
+			if d.nodes[node] {
+				style = fmt.Sprintf(" #line:%s;line.bold", d.color)
+			}
+			fmt.Fprintf(bw, "%srectangle \"%s\" as n%d%s\n", indent, plantUMLEscape(diagramLabel(g, node, opts)), node, style)
+		}
+	}
+	for _, cluster := range d.clusters {
+		fmt.Fprintf(bw, "package \"%s\" {\n", plantUMLEscape(cluster))
+		writeNodes(d.members[cluster], "  ")
+		bw.WriteString("}\n")
+	}
+	writeNodes(d.members[""], "")
+
+	for _, edge := range d.edges {
+		style := ""
+		if d.highlight[[2]uint64{edge.u, edge.v}] {
+			style = fmt.Sprintf("[#%s,bold]", d.color)
+		}
+		arrow := "-" + style + "-"
+		if g.directed {
+			arrow += ">"
+		}
+		label := ""
+		if opts.Weight {
+			label = fmt.Sprintf(" : %d", edge.Weight)
+		}
+		fmt.Fprintf(bw, "n%d %s n%d%s\n", edge.u, arrow, edge.v, label)
+	}
+
+	bw.WriteString("@enduml\n")
+	return bw.Flush()
+}
+
+// diagramDirection validates the layout direction, defaulting to "TB".
+func diagramDirection(direction string) (string, error) {
+	switch direction {
+	case "":
+		return "TB", nil
+	case "TB", "BT", "LR", "RL":
+		return direction, nil
+	}
+	return "", fmt.Errorf("Invalid direction %q", direction)
+}
+
+// mermaidEscape escapes s to be used inside a quoted Mermaid string.
+var mermaidEscape = strings.NewReplacer(`"`, "#quot;", "\n", "<br/>").Replace
+
+// plantUMLEscape makes s safe to be used inside a quoted PlantUML string.
+var plantUMLEscape = strings.NewReplacer(`"`, "'", "\n", `\n`).Replace
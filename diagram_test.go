@@ -0,0 +1,118 @@
+package grapho
+
+import (
+	"bytes"
+	"testing"
+)
+
+func diagramGraph(directed bool) *Graph {
+	g := NewGraph(directed)
+	g.AddNode(1, Attr{"name": `Node "A"`, "group": "core"})
+	g.AddNode(2, Attr{"name": "B", "group": "core"})
+	g.AddNode(3, Attr{"group": "api"})
+	g.AddNode(4, nil)
+	g.AddEdge(1, 2, 3, nil)
+	g.AddEdge(2, 3, 1, nil)
+	g.AddEdge(3, 4, 2, nil)
+	return g
+}
+
+func TestWriteMermaid(t *testing.T) {
+	opts := &DiagramOptions{Label: "name", Cluster: "group", Weight: true, Direction: "LR", Path: []uint64{1, 2, 3}}
+
+	var buf bytes.Buffer
+	if err := WriteMermaid(&buf, diagramGraph(true), opts); err != nil {
+		t.Fatalf("WriteMermaid: %v", err)
+	}
+	expected := `flowchart LR
+    subgraph cluster0 ["api"]
+        n3["3"]
+    end
+    subgraph cluster1 ["core"]
+        n1["Node #quot;A#quot;"]
+        n2["B"]
+    end
+    n4["4"]
+    n1 -->|3| n2
+    n2 -->|1| n3
+    n3 -->|2| n4
+    classDef highlight stroke:red,stroke-width:2px
+    class n1,n2,n3 highlight
+    linkStyle 0,1 stroke:red,stroke-width:2px
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WriteMermaid(&buf, diagramGraph(false), &DiagramOptions{Keyword: "graph", Path: []uint64{4, 3}}); err != nil {
+		t.Fatalf("WriteMermaid: %v", err)
+	}
+	expected = `graph TB
+    n1["1"]
+    n2["2"]
+    n3["3"]
+    n4["4"]
+    n1 --- n2
+    n2 --- n3
+    n3 --- n4
+    classDef highlight stroke:red,stroke-width:2px
+    class n3,n4 highlight
+    linkStyle 2 stroke:red,stroke-width:2px
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+
+	if err := WriteMermaid(&buf, sampleGraph(), &DiagramOptions{Keyword: "sequence"}); err == nil {
+		t.Error("Expected error with an invalid keyword")
+	}
+	if err := WriteMermaid(&buf, sampleGraph(), &DiagramOptions{Direction: "up"}); err == nil {
+		t.Error("Expected error with an invalid direction")
+	}
+}
+
+func TestWritePlantUML(t *testing.T) {
+	opts := &DiagramOptions{Label: "name", Cluster: "group", Weight: true, Direction: "LR", Path: []uint64{1, 2}, Color: "blue"}
+
+	var buf bytes.Buffer
+	if err := WritePlantUML(&buf, diagramGraph(true), opts); err != nil {
+		t.Fatalf("WritePlantUML: %v", err)
+	}
+	expected := `@startuml
+left to right direction
+package "api" {
+  rectangle "3" as n3
+}
+package "core" {
+  rectangle "Node 'A'" as n1 #line:blue;line.bold
+  rectangle "B" as n2 #line:blue;line.bold
+}
+rectangle "4" as n4
+n1 -[#blue,bold]-> n2 : 3
+n2 --> n3 : 1
+n3 --> n4 : 2
+@enduml
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := WritePlantUML(&buf, diagramGraph(false), nil); err != nil {
+		t.Fatalf("WritePlantUML: %v", err)
+	}
+	expected = `@startuml
+rectangle "1" as n1
+rectangle "2" as n2
+rectangle "3" as n3
+rectangle "4" as n4
+n1 -- n2
+n2 -- n3
+n3 -- n4
+@enduml
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,307 @@
+// DIMACS challenge formats
+// Shortest paths (9th challenge): http://www.diag.uniroma1.it/challenge9/format.shtml
+// Maximum flow (1st challenge): http://lpsolve.sourceforge.net/5.5/DIMACS_maxf.htm
+
+package grapho
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Node Attr keys holding the coordinates read from DIMACS .co files.
+const (
+	DIMACSXKey = "x"
+	DIMACSYKey = "y"
+)
+
+// dimacsReader parses the lines of a DIMACS file, checking the problem line comes first.
+type dimacsReader struct {
+	problem string // expected problem type, i.e. "sp"
+	n       int    // number of nodes declared in the problem line
+	seen    bool   // whether the problem line was found
+}
+
+// parseProblem parses the problem line: "p <problem> n m".
+func (d *dimacsReader) parseProblem(fields []string) error {
+	if d.seen {
+		return errors.New("Duplicated problem line")
+	}
+	d.seen = true
+
+	args := fields[1:]
+	if d.problem == "co" {
+		if len(args) != 4 || args[0] != "aux" || args[1] != "sp" || args[2] != "co" {
+			return errors.New("Expected problem line: p aux sp co n")
+		}
+		args = args[2:] // count the nodes only
+	} else if len(args) != 3 || args[0] != d.problem {
+		return fmt.Errorf("Expected problem line: p %s n m", d.problem)
+	}
+
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 0 {
+		return fmt.Errorf("Invalid number of nodes %q", args[1])
+	}
+	d.n = n
+	return nil
+}
+
+// checkNode parses a node id, checking it is in the range 1..n.
+func (d *dimacsReader) checkNode(s string) (uint64, error) {
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || id < 1 || id > uint64(d.n) {
+		return 0, fmt.Errorf("Invalid node %q", s)
+	}
+	return id, nil
+}
+
+// read scans the file, passing every descriptor line (after the problem line) to fn.
+func (d *dimacsReader) read(r io.Reader, fn func(fields []string) error) error {
+	return scanFields(r, "c", func(line int, fields []string) error {
+		if fields[0] == "p" {
+			return d.parseProblem(fields)
+		}
+		if !d.seen {
+			return errors.New("Descriptor found before the problem line")
+		}
+		return fn(fields)
+	})
+}
+
+// parseInts parses a list of decimal integers.
+func parseInts(fields []string) ([]int, error) {
+	values := make([]int, len(fields))
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid integer %q", f)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// arc parses an arc descriptor: "a u v w".
+func (d *dimacsReader) arc(fields []string) (u, v uint64, w int, err error) {
+	if len(fields) != 4 {
+		return 0, 0, 0, errors.New("Expected arc descriptor: a u v w")
+	}
+	if u, err = d.checkNode(fields[1]); err != nil {
+		return
+	}
+	if v, err = d.checkNode(fields[2]); err != nil {
+		return
+	}
+	if w, err = strconv.Atoi(fields[3]); err != nil {
+		err = fmt.Errorf("Invalid weight %q", fields[3])
+	}
+	return
+}
+
+// ReadDIMACS builds a directed Graph from a DIMACS shortest path (.gr) file.
+// Nodes are given the ids 1..n declared in the problem line, and every arc becomes an edge.
+// Files declaring over 2^20 more nodes than their arcs reference are rejected.
+func ReadDIMACS(r io.Reader) (*Graph, error) {
+	d := &dimacsReader{problem: "sp"}
+	g := NewGraph(true)
+
+	err := d.read(r, func(fields []string) error {
+		if fields[0] != "a" {
+			return fmt.Errorf("Unexpected descriptor %q", fields[0])
+		}
+		u, v, w, err := d.arc(fields)
+		if err != nil {
+			return err
+		}
+		g.AddEdge(u, v, w, nil)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !d.seen {
+		return nil, errors.New("Missing problem line")
+	}
+
+	if err := addDeclaredNodes(g, d.n); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// WriteDIMACS writes the Graph as a DIMACS shortest path (.gr) file. Nodes are numbered 1..n
+// following the Graph Index order, so graphs whose ids are already 1..n are written as is.
+// In undirected graphs, every edge is written as two arcs.
+func WriteDIMACS(w io.Writer, g *Graph) error {
+	return writeDIMACSArcs(w, g, "sp", nil)
+}
+
+// ReadDIMACSFlow builds a directed Graph from a DIMACS maximum flow file, returning the source
+// and sink nodes too. Arc capacities are read as Edge.Weight. Declared nodes are bounded as in ReadDIMACS.
+func ReadDIMACSFlow(r io.Reader) (g *Graph, source, sink uint64, err error) {
+	d := &dimacsReader{problem: "max"}
+	g = NewGraph(true)
+
+	err = d.read(r, func(fields []string) error {
+		switch fields[0] {
+		case "n":
+			if len(fields) != 3 {
+				return errors.New("Expected node descriptor: n id s|t")
+			}
+			id, err := d.checkNode(fields[1])
+			if err != nil {
+				return err
+			}
+			switch fields[2] {
+			case "s":
+				source = id
+			case "t":
+				sink = id
+			default:
+				return fmt.Errorf("Invalid node designation %q", fields[2])
+			}
+		case "a":
+			u, v, capacity, err := d.arc(fields)
+			if err != nil {
+				return err
+			}
+			g.AddEdge(u, v, capacity, nil)
+		default:
+			return fmt.Errorf("Unexpected descriptor %q", fields[0])
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if !d.seen {
+		return nil, 0, 0, errors.New("Missing problem line")
+	}
+	if source == 0 || sink == 0 {
+		return nil, 0, 0, errors.New("Missing source or sink node")
+	}
+
+	g.AddNodeIfAbsent(source, nil)
+	g.AddNodeIfAbsent(sink, nil)
+	if err := addDeclaredNodes(g, d.n); err != nil {
+		return nil, 0, 0, err
+	}
+	return g, source, sink, nil
+}
+
+// WriteDIMACSFlow writes the Graph as a DIMACS maximum flow file, with Edge.Weight as the arc capacity.
+// Nodes are numbered as in WriteDIMACS.
+func WriteDIMACSFlow(w io.Writer, g *Graph, source, sink uint64) error {
+	x := g.Index()
+	s, ok := x.Pos(source)
+	if !ok {
+		return fmt.Errorf("Source node %d not found", source)
+	}
+	t, ok := x.Pos(sink)
+	if !ok {
+		return fmt.Errorf("Sink node %d not found", sink)
+	}
+	return writeDIMACSArcs(w, g, "max", []string{fmt.Sprintf("n %d s", s+1), fmt.Sprintf("n %d t", t+1)})
+}
+
+// writeDIMACSArcs writes the problem line, the given descriptors and every arc of the Graph.
+func writeDIMACSArcs(w io.Writer, g *Graph, problem string, descriptors []string) error {
+	x := g.Index()
+	edges := g.sortedEdges()
+
+	arcs := len(edges)
+	if !g.directed {
+		arcs *= 2
+		for _, edge := range edges {
+			if edge.u == edge.v {
+				arcs-- // self-loops are written once
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "p %s %d %d\n", problem, x.Len(), arcs)
+	for _, d := range descriptors {
+		fmt.Fprintln(bw, d)
+	}
+	for _, edge := range edges {
+		u, _ := x.Pos(edge.u)
+		v, _ := x.Pos(edge.v)
+		fmt.Fprintf(bw, "a %d %d %d\n", u+1, v+1, edge.Weight)
+		if !g.directed && u != v {
+			fmt.Fprintf(bw, "a %d %d %d\n", v+1, u+1, edge.Weight)
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadDIMACSCoords reads a DIMACS coordinates (.co) file into the given Graph, storing the
+// coordinates of every node as int values in its DIMACSXKey and DIMACSYKey attributes.
+// Nodes not present in the Graph are created.
+func ReadDIMACSCoords(r io.Reader, g *Graph) error {
+	d := &dimacsReader{problem: "co"}
+	err := d.read(r, func(fields []string) error {
+		if fields[0] != "v" || len(fields) != 4 {
+			return errors.New("Expected coordinate descriptor: v id x y")
+		}
+		id, err := d.checkNode(fields[1])
+		if err != nil {
+			return err
+		}
+		coords, err := parseInts(fields[2:])
+		if err != nil {
+			return err
+		}
+
+		g.AddNodeIfAbsent(id, nil)
+		g.SetNodeAttr(id, DIMACSXKey, coords[0])
+		g.SetNodeAttr(id, DIMACSYKey, coords[1])
+		return nil
+	})
+	if err == nil && !d.seen {
+		err = errors.New("Missing problem line")
+	}
+	return err
+}
+
+// WriteDIMACSCoords writes the int DIMACSXKey and DIMACSYKey attributes of the Graph nodes as a
+// DIMACS coordinates (.co) file. Nodes are numbered as in WriteDIMACS. Nodes without coordinates are skipped.
+func WriteDIMACSCoords(w io.Writer, g *Graph) error {
+	x := g.Index()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "p aux sp co %d\n", x.Len())
+	for i, node := range x.ids {
+		cx, okx := g.nodes[node][DIMACSXKey].(int)
+		cy, oky := g.nodes[node][DIMACSYKey].(int)
+		if okx && oky {
+			fmt.Fprintf(bw, "v %d %d %d\n", i+1, cx, cy)
+		}
+	}
+	return bw.Flush()
+}
+
+// EuclideanHeuristic returns an A* Heuristic estimating the cost between two nodes as their
+// euclidean distance, computed from their int DIMACSXKey and DIMACSYKey attributes and multiplied
+// by scale (rounded down). For the search to be optimal, scale must be chosen so that the estimate never
+// exceeds the actual path cost. Nodes without coordinates are estimated with 0.
+func EuclideanHeuristic(g *Graph, scale float64) Heuristic {
+	return func(node, goal uint64) int {
+		na, _ := g.Node(node)
+		ga, _ := g.Node(goal)
+		nx, ok1 := na[DIMACSXKey].(int)
+		ny, ok2 := na[DIMACSYKey].(int)
+		gx, ok3 := ga[DIMACSXKey].(int)
+		gy, ok4 := ga[DIMACSYKey].(int)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return 0
+		}
+		dx, dy := float64(nx-gx), float64(ny-gy)
+		return int(math.Floor(math.Sqrt(dx*dx+dy*dy) * scale))
+	}
+}
@@ -0,0 +1,117 @@
+package grapho
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDIMACS(t *testing.T) {
+	src := `c 9th DIMACS Implementation Challenge: Shortest Paths
+p sp 4 4
+a 1 2 10
+a 2 3 10
+a 1 3 25
+a 3 1 5
+`
+	g, err := ReadDIMACS(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadDIMACS: %v", err)
+	}
+	if !g.IsDirected() || g.Len() != 4 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", g.IsDirected(), g.Len())
+	}
+
+	coords := `p aux sp co 4
+v 1 0 0
+v 2 6 8
+v 3 12 16
+v 4 100 100
+`
+	if err := ReadDIMACSCoords(strings.NewReader(coords), g); err != nil {
+		t.Fatalf("ReadDIMACSCoords: %v", err)
+	}
+	if attr, _ := g.Node(2); attr[DIMACSXKey] != 6 || attr[DIMACSYKey] != 8 {
+		t.Errorf("Unexpected attributes for node 2: %v", attr)
+	}
+
+	h := EuclideanHeuristic(g, 1)
+	if d := h(1, 3); d != 20 {
+		t.Errorf("Heuristic(1, 3): %d. Expected 20", d)
+	}
+	path, err := Search(g, 1, 3, Astar, h)
+	if err != nil || !equalPath(path, []uint64{1, 2, 3}) {
+		t.Errorf("Unexpected path: %v (%v)", path, err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDIMACS(&buf, g); err != nil {
+		t.Fatalf("WriteDIMACS: %v", err)
+	}
+	expected := "p sp 4 4\na 1 2 10\na 1 3 25\na 2 3 10\na 3 1 5\n"
+	if buf.String() != expected {
+		t.Errorf("WriteDIMACS: %q. Expected %q", buf.String(), expected)
+	}
+
+	buf.Reset()
+	if err := WriteDIMACSCoords(&buf, g); err != nil {
+		t.Fatalf("WriteDIMACSCoords: %v", err)
+	}
+	if buf.String() != coords {
+		t.Errorf("WriteDIMACSCoords: %q. Expected %q", buf.String(), coords)
+	}
+
+	for src, line := range map[string]int{
+		"a 1 2 3\n":                 1,
+		"p sp 2 1\na 1 3 1\n":       2,
+		"p sp 2 1\nc\na 1 2 x\n":    3,
+		"p max 2 1\n":               1,
+		"p sp 2 1\nv 1 2\n":         2,
+		"p sp 2 1\np sp 2 1\n":      2,
+		"p sp 2 1\na 1 2 3 4 5\n":   2,
+		"c only comments\np sp x 1": 2,
+	} {
+		_, err := ReadDIMACS(strings.NewReader(src))
+		if pe, ok := err.(*ParseError); !ok || pe.Line != line {
+			t.Errorf("ReadDIMACS(%q): Expected error on line %d, got %v", src, line, err)
+		}
+	}
+
+	// Isolated nodes are not bounded by the input length
+	if _, err := ReadDIMACS(strings.NewReader("p sp 2000000000 0\n")); err == nil {
+		t.Error("ReadDIMACS: Did not get expected error with 2000000000 isolated nodes")
+	}
+	if _, _, _, err := ReadDIMACSFlow(strings.NewReader("p max 2000000000 0\nn 1 s\nn 2 t\n")); err == nil {
+		t.Error("ReadDIMACSFlow: Did not get expected error with 2000000000 isolated nodes")
+	}
+}
+
+func TestDIMACSFlow(t *testing.T) {
+	g := NewGraph(false)
+	g.AddEdge(10, 20, 3, nil)
+	g.AddEdge(20, 30, 2, nil)
+
+	var buf bytes.Buffer
+	if err := WriteDIMACSFlow(&buf, g, 10, 30); err != nil {
+		t.Fatalf("WriteDIMACSFlow: %v", err)
+	}
+	expected := "p max 3 4\nn 1 s\nn 3 t\na 1 2 3\na 2 1 3\na 2 3 2\na 3 2 2\n"
+	if buf.String() != expected {
+		t.Errorf("WriteDIMACSFlow: %q. Expected %q", buf.String(), expected)
+	}
+
+	read, source, sink, err := ReadDIMACSFlow(&buf)
+	if err != nil {
+		t.Fatalf("ReadDIMACSFlow: %v", err)
+	}
+	if source != 1 || sink != 3 || read.Len() != 3 {
+		t.Errorf("Unexpected flow network: source %d, sink %d, %d nodes", source, sink, read.Len())
+	}
+	if edge, ok := read.Edge(3, 2); !ok || edge.Weight != 2 {
+		t.Errorf("Unexpected edge 3-2: %v", edge)
+	}
+
+	if _, _, _, err := ReadDIMACSFlow(strings.NewReader("p max 2 1\na 1 2 1\n")); err == nil {
+		t.Errorf("ReadDIMACSFlow: Did not get expected error without source and sink")
+	}
+}
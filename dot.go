@@ -0,0 +1,704 @@
+// Graphviz DOT language
+// http://www.graphviz.org/doc/info/lang.html
+
+package grapho
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DOTOptions configures how a Graph is written in the DOT language.
+type DOTOptions struct {
+	Name      string            // Graph name. Omitted if empty
+	NodeAttr  map[string]string // Node Attr keys to write, bound to their DOT attribute name. If nil, every key is written as is
+	EdgeAttr  map[string]string // Edge Attr keys to write, bound to their DOT attribute name. If nil, every key is written as is
+	Weight    string            // DOT attribute holding Edge.Weight, i.e. "label" or "weight". Omitted if empty
+	Path      []uint64          // Path to highlight, as returned by Search
+	Highlight *Graph            // Graph whose nodes and edges will be highlighted, i.e. a Minimum Spanning Tree
+	Color     string            // Color of highlighted nodes and edges. Defaults to "red"
+}
+
+// WriteDOT writes the Graph in the DOT language, as a digraph or graph depending on its direction.
+// Nodes and edges are written in ascending node order, so the output is deterministic.
+func WriteDOT(w io.Writer, g *Graph, opts *DOTOptions) error {
+	if opts == nil {
+		opts = &DOTOptions{}
+	}
+	color := opts.Color
+	if color == "" {
+		color = "red"
+	}
+
+	// Collect highlighted nodes and edges
+	nodes := make(map[uint64]bool)
+	edges := make(map[[2]uint64]bool)
+	highlight := func(u, v uint64) {
+		if !g.directed && u > v {
+			u, v = v, u
+		}
+		edges[[2]uint64{u, v}] = true
+	}
+	for i, node := range opts.Path {
+		nodes[node] = true
+		if i > 0 {
+			highlight(opts.Path[i-1], node)
+		}
+	}
+	if opts.Highlight != nil {
+		for _, node := range opts.Highlight.Nodes() {
+			nodes[node] = true
+		}
+		for _, edge := range opts.Highlight.sortedEdges() {
+			highlight(edge.u, edge.v)
+			if !opts.Highlight.directed {
+				highlight(edge.v, edge.u)
+			}
+		}
+	}
+
+	kind, op := "graph", "--"
+	if g.directed {
+		kind, op = "digraph", "->"
+	}
+
+	bw := bufio.NewWriter(w)
+	if opts.Name != "" {
+		fmt.Fprintf(bw, "%s %s {\n", kind, dotID(opts.Name))
+	} else {
+		fmt.Fprintf(bw, "%s {\n", kind)
+	}
+
+	for _, node := range g.sortedNodeIDs() {
+		attrs := dotAttrs(g.nodes[node], opts.NodeAttr)
+		if nodes[node] {
+			attrs = append(attrs, "color="+dotID(color))
+		}
+		fmt.Fprintf(bw, "\t%d%s;\n", node, dotAttrList(attrs))
+	}
+
+	for _, edge := range g.sortedEdges() {
+		attrs := dotAttrs(edge.Attr, opts.EdgeAttr)
+		if opts.Weight != "" {
+			attrs = append(attrs, fmt.Sprintf("%s=%d", dotID(opts.Weight), edge.Weight))
+		}
+		if edges[[2]uint64{edge.u, edge.v}] {
+			attrs = append(attrs, "color="+dotID(color), "penwidth=2")
+		}
+		fmt.Fprintf(bw, "\t%d %s %d%s;\n", edge.u, op, edge.v, dotAttrList(attrs))
+	}
+
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// dotAttrs returns the given attributes as DOT a_list entries (key=value), sorted by Attr key.
+// If mapping is not nil, only its keys are written, renamed to their mapped value.
+func dotAttrs(attr Attr, mapping map[string]string) []string {
+	var attrs []string
+	for _, k := range sortedKeys(attr) {
+		name := k
+		if mapping != nil {
+			var ok bool
+			if name, ok = mapping[k]; !ok {
+				continue
+			}
+		}
+		attrs = append(attrs, dotID(name)+"="+dotID(fmt.Sprint(attr[k])))
+	}
+	return attrs
+}
+
+// dotAttrList formats a DOT attribute list, or an empty string if there are no attributes.
+func dotAttrList(attrs []string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(attrs, ", ") + "]"
+}
+
+// dotNumeral matches a DOT numeral ID
+var dotNumeral = regexp.MustCompile(`^-?(\.[0-9]+|[0-9]+(\.[0-9]*)?)$`)
+
+// dotID formats s as a DOT ID, quoting it unless it is a plain identifier or a numeral.
+// Backslashes are escaped, so that a trailing one does not escape the closing quote.
+func dotID(s string) string {
+	if dotNumeral.MatchString(s) {
+		return s
+	}
+	switch strings.ToLower(s) {
+	case "node", "edge", "graph", "digraph", "subgraph", "strict":
+		return `"` + s + `"`
+	}
+
+	plain := s != ""
+	for i, r := range s {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return s
+	}
+
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return `"` + s + `"`
+}
+
+// dotToken kinds
+const (
+	dotEOF = iota
+	dotIDToken
+	dotPunct // single char punctuation: { } [ ] ; , = : +
+	dotEdgeOp
+)
+
+type dotToken struct {
+	kind   int
+	value  string
+	quoted bool // ID written as a quoted or HTML string, thus never a keyword
+	line   int
+}
+
+// dotLexer splits DOT source into tokens.
+type dotLexer struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func (l *dotLexer) errorf(format string, args ...interface{}) error {
+	return &ParseError{l.line, fmt.Errorf(format, args...)}
+}
+
+// skip ignores white space and comments.
+func (l *dotLexer) skip() {
+	bol := l.pos == 0 // beginning of line
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.line++
+			l.pos++
+			bol = true
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			l.pos++
+			continue
+		case c == '#' && bol, c == '/' && l.peek(1) == '/':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+			continue
+		case c == '/' && l.peek(1) == '*':
+			l.pos += 2
+			for l.pos < len(l.src) && !(l.src[l.pos] == '*' && l.peek(1) == '/') {
+				if l.src[l.pos] == '\n' {
+					l.line++
+				}
+				l.pos++
+			}
+			l.pos += 2
+			continue
+		}
+		return
+	}
+}
+
+func (l *dotLexer) peek(offset int) rune {
+	if l.pos+offset < len(l.src) {
+		return l.src[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *dotLexer) next() (dotToken, error) {
+	l.skip()
+	if l.pos >= len(l.src) {
+		return dotToken{kind: dotEOF, line: l.line}, nil
+	}
+
+	line := l.line
+	c := l.src[l.pos]
+	switch {
+	case strings.ContainsRune("{}[];,=:+", c):
+		l.pos++
+		return dotToken{dotPunct, string(c), false, line}, nil
+	case c == '-' && (l.peek(1) == '-' || l.peek(1) == '>'):
+		l.pos += 2
+		return dotToken{dotEdgeOp, string(l.src[l.pos-2 : l.pos]), false, line}, nil
+	case c == '"':
+		var buf []rune
+		for l.pos++; l.pos < len(l.src) && l.src[l.pos] != '"'; l.pos++ {
+			switch {
+			case l.src[l.pos] == '\\' && (l.peek(1) == '"' || l.peek(1) == '\\'):
+				l.pos++
+			case l.src[l.pos] == '\\' && l.peek(1) == '\n': // line continuation
+				l.pos++
+				l.line++
+				continue
+			case l.src[l.pos] == '\n':
+				l.line++
+			}
+			buf = append(buf, l.src[l.pos])
+		}
+		if l.pos >= len(l.src) {
+			return dotToken{}, l.errorf("Unterminated string")
+		}
+		l.pos++
+		return dotToken{dotIDToken, string(buf), true, line}, nil
+	case c == '<':
+		start, depth := l.pos+1, 0
+		for ; l.pos < len(l.src); l.pos++ {
+			switch l.src[l.pos] {
+			case '<':
+				depth++
+			case '>':
+				depth--
+			case '\n':
+				l.line++
+			}
+			if depth == 0 {
+				break
+			}
+		}
+		if l.pos >= len(l.src) {
+			return dotToken{}, l.errorf("Unterminated HTML string")
+		}
+		l.pos++
+		return dotToken{dotIDToken, string(l.src[start : l.pos-1]), true, line}, nil
+	case c == '-' || c == '.' || c >= '0' && c <= '9':
+		start := l.pos
+		l.pos++
+		for l.pos < len(l.src) && (l.src[l.pos] == '.' || l.src[l.pos] >= '0' && l.src[l.pos] <= '9') {
+			l.pos++
+		}
+		value := string(l.src[start:l.pos])
+		if !dotNumeral.MatchString(value) {
+			return dotToken{}, l.errorf("Invalid numeral %q", value)
+		}
+		return dotToken{dotIDToken, value, false, line}, nil
+	case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80:
+		start := l.pos
+		for l.pos < len(l.src) {
+			c = l.src[l.pos]
+			if !(c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80) {
+				break
+			}
+			l.pos++
+		}
+		return dotToken{dotIDToken, string(l.src[start:l.pos]), false, line}, nil
+	}
+
+	return dotToken{}, l.errorf("Unexpected character %q", c)
+}
+
+// dotScope holds the default attributes of a graph or subgraph,
+// and the nodes found in it.
+type dotScope struct {
+	node, edge map[string]string
+	nodes      []string
+}
+
+func (s *dotScope) child() *dotScope {
+	c := &dotScope{make(map[string]string), make(map[string]string), nil}
+	for k, v := range s.node {
+		c.node[k] = v
+	}
+	for k, v := range s.edge {
+		c.edge[k] = v
+	}
+	return c
+}
+
+type dotEdge struct {
+	u, v  string
+	attrs map[string]string
+	line  int
+}
+
+// dotParser builds the list of nodes and edges described by DOT source.
+type dotParser struct {
+	lex      *dotLexer
+	tok      dotToken
+	directed bool
+	names    []string                     // Node names, in order of appearance
+	nodes    map[string]map[string]string // Node attributes
+	edges    []dotEdge
+}
+
+func (p *dotParser) advance() error {
+	tok, err := p.lex.next()
+	p.tok = tok
+	return err
+}
+
+func (p *dotParser) errorf(format string, args ...interface{}) error {
+	return &ParseError{p.tok.line, fmt.Errorf(format, args...)}
+}
+
+// keyword returns whether the current token is the given (case-insensitive) keyword.
+func (p *dotParser) keyword(kw string) bool {
+	return p.tok.kind == dotIDToken && !p.tok.quoted && strings.EqualFold(p.tok.value, kw)
+}
+
+func (p *dotParser) punct(c string) bool {
+	return p.tok.kind == dotPunct && p.tok.value == c
+}
+
+func (p *dotParser) expect(c string) error {
+	if !p.punct(c) {
+		return p.errorf("Expected '%s', found %q", c, p.tok.value)
+	}
+	return p.advance()
+}
+
+// id parses an ID, concatenating quoted strings joined with '+'.
+func (p *dotParser) id() (string, error) {
+	if p.tok.kind != dotIDToken {
+		return "", p.errorf("Expected ID, found %q", p.tok.value)
+	}
+	value, quoted := p.tok.value, p.tok.quoted
+	if err := p.advance(); err != nil {
+		return "", err
+	}
+	for quoted && p.punct("+") {
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		if p.tok.kind != dotIDToken || !p.tok.quoted {
+			return "", p.errorf("Expected string after '+'")
+		}
+		value += p.tok.value
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+	}
+	return value, nil
+}
+
+func (p *dotParser) graph() error {
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.keyword("strict") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case p.keyword("graph"):
+		p.directed = false
+	case p.keyword("digraph"):
+		p.directed = true
+	default:
+		return p.errorf("Expected 'graph' or 'digraph'")
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.tok.kind == dotIDToken {
+		if _, err := p.id(); err != nil {
+			return err
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	scope := &dotScope{make(map[string]string), make(map[string]string), nil}
+	if err := p.stmtList(scope); err != nil {
+		return err
+	}
+	if err := p.expect("}"); err != nil {
+		return err
+	}
+	if p.tok.kind != dotEOF {
+		return p.errorf("Unexpected %q after graph", p.tok.value)
+	}
+	return nil
+}
+
+func (p *dotParser) stmtList(scope *dotScope) error {
+	for !p.punct("}") {
+		if p.tok.kind == dotEOF {
+			return p.errorf("Unexpected end of input")
+		}
+		if err := p.stmt(scope); err != nil {
+			return err
+		}
+		if p.punct(";") {
+			if err := p.advance(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *dotParser) stmt(scope *dotScope) error {
+	switch {
+	case p.keyword("graph"), p.keyword("node"), p.keyword("edge"):
+		kind := strings.ToLower(p.tok.value)
+		if err := p.advance(); err != nil {
+			return err
+		}
+		attrs, err := p.attrList()
+		if err != nil {
+			return err
+		}
+		var defaults map[string]string
+		switch kind {
+		case "node":
+			defaults = scope.node
+		case "edge":
+			defaults = scope.edge
+		default:
+			return nil // graph attributes are ignored
+		}
+		for k, v := range attrs {
+			defaults[k] = v
+		}
+		return nil
+	case p.keyword("subgraph"), p.punct("{"):
+		nodes, err := p.subgraph(scope)
+		if err != nil {
+			return err
+		}
+		return p.edgeRHS(scope, nodes)
+	}
+
+	name, err := p.id()
+	if err != nil {
+		return err
+	}
+	if p.punct("=") { // graph attribute
+		if err := p.advance(); err != nil {
+			return err
+		}
+		_, err := p.id()
+		return err
+	}
+	if err := p.port(); err != nil {
+		return err
+	}
+
+	if p.tok.kind == dotEdgeOp {
+		p.node(scope, name, nil)
+		return p.edgeRHS(scope, []string{name})
+	}
+
+	attrs, err := p.attrList()
+	if err != nil {
+		return err
+	}
+	p.node(scope, name, attrs)
+	return nil
+}
+
+// port skips the optional port of a node id.
+func (p *dotParser) port() error {
+	for i := 0; i < 2 && p.punct(":"); i++ {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if _, err := p.id(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// node declares a node, applying the scope defaults if it is a new one.
+func (p *dotParser) node(scope *dotScope, name string, attrs map[string]string) {
+	current, ok := p.nodes[name]
+	if !ok {
+		current = make(map[string]string)
+		for k, v := range scope.node {
+			current[k] = v
+		}
+		p.nodes[name] = current
+		p.names = append(p.names, name)
+	}
+	for k, v := range attrs {
+		current[k] = v
+	}
+	scope.nodes = append(scope.nodes, name)
+}
+
+// subgraph parses a subgraph, returning the nodes declared in it.
+func (p *dotParser) subgraph(scope *dotScope) ([]string, error) {
+	if p.keyword("subgraph") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == dotIDToken {
+			if _, err := p.id(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	child := scope.child()
+	if err := p.stmtList(child); err != nil {
+		return nil, err
+	}
+	scope.nodes = append(scope.nodes, child.nodes...)
+	return child.nodes, p.expect("}")
+}
+
+// edgeRHS parses the right hand side of an edge statement (if any), whose first operand are the given nodes.
+func (p *dotParser) edgeRHS(scope *dotScope, from []string) error {
+	type operands struct {
+		nodes []string
+		line  int
+	}
+	chain := []operands{{from, p.tok.line}}
+
+	for p.tok.kind == dotEdgeOp {
+		if (p.tok.value == "->") != p.directed {
+			return p.errorf("Edge operator %s not allowed in this graph", p.tok.value)
+		}
+		line := p.tok.line
+		if err := p.advance(); err != nil {
+			return err
+		}
+
+		var nodes []string
+		if p.keyword("subgraph") || p.punct("{") {
+			var err error
+			if nodes, err = p.subgraph(scope); err != nil {
+				return err
+			}
+		} else {
+			name, err := p.id()
+			if err != nil {
+				return err
+			}
+			if err := p.port(); err != nil {
+				return err
+			}
+			p.node(scope, name, nil)
+			nodes = []string{name}
+		}
+		chain = append(chain, operands{nodes, line})
+	}
+	if len(chain) == 1 {
+		return nil
+	}
+
+	attrs, err := p.attrList()
+	if err != nil {
+		return err
+	}
+	for i := 1; i < len(chain); i++ {
+		for _, u := range chain[i-1].nodes {
+			for _, v := range chain[i].nodes {
+				edge := dotEdge{u, v, make(map[string]string), chain[i].line}
+				for k, v := range scope.edge {
+					edge.attrs[k] = v
+				}
+				for k, v := range attrs {
+					edge.attrs[k] = v
+				}
+				p.edges = append(p.edges, edge)
+			}
+		}
+	}
+	return nil
+}
+
+// attrList parses zero or more bracketed attribute lists.
+func (p *dotParser) attrList() (map[string]string, error) {
+	attrs := make(map[string]string)
+	for p.punct("[") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for !p.punct("]") {
+			key, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("="); err != nil {
+				return nil, err
+			}
+			value, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			attrs[key] = value
+
+			if p.punct(",") || p.punct(";") {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return attrs, nil
+}
+
+// ReadDOT builds a Graph from DOT source. Subgraphs are flattened into the main graph, and
+// graph attributes are ignored. Node and edge attributes are stored in Attr, as strings, with the
+// escaped quotes and backslashes of quoted strings unescaped.
+// The edge attribute named weight (if not empty) must be an integer, and is read as Edge.Weight
+// instead. Edges without it are given a weight of 1.
+// DOT node names are bound to node ids as follows: if every name is a decimal uint64, names are
+// used as ids. Otherwise, ids are assigned sequentially, starting at 1, in order of appearance.
+// The returned map binds each DOT node name to its node id.
+func ReadDOT(r io.Reader, weight string) (*Graph, map[string]uint64, error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := &dotParser{
+		lex:   &dotLexer{src: []rune(string(src)), line: 1},
+		nodes: make(map[string]map[string]string),
+	}
+	if err := p.graph(); err != nil {
+		return nil, nil, err
+	}
+
+	ids := assignIDs(p.names)
+	g := NewGraph(p.directed)
+	for _, name := range p.names {
+		attr := NewAttr()
+		for k, v := range p.nodes[name] {
+			attr[k] = v
+		}
+		g.AddNode(ids[name], attr)
+	}
+
+	for _, edge := range p.edges {
+		w := 1
+		attr := NewAttr()
+		for k, v := range edge.attrs {
+			if k == weight {
+				if w, err = strconv.Atoi(v); err != nil {
+					return nil, nil, &ParseError{edge.line, fmt.Errorf("Invalid weight %q", v)}
+				}
+				continue
+			}
+			attr[k] = v
+		}
+		g.AddEdge(ids[edge.u], ids[edge.v], w, attr)
+	}
+
+	return g, ids, nil
+}
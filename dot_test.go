@@ -0,0 +1,166 @@
+package grapho
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	g := NewGraph(false)
+	attr := NewAttr()
+	attr["name"] = "Bob"
+	attr["age"] = 30
+	g.AddNode(1, attr)
+	g.AddEdge(2, 1, 5, nil)
+	g.AddEdge(3, 2, 1, Attr{"kind": `a "b"`})
+
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, g, &DOTOptions{Name: "g", Weight: "label", Path: []uint64{1, 2}}); err != nil {
+		t.Fatalf("WriteDOT: %v", err)
+	}
+
+	expected := `graph g {
+	1 [age=30, name=Bob, color=red];
+	2 [color=red];
+	3;
+	1 -- 2 [label=5, color=red, penwidth=2];
+	2 -- 3 [kind="a \"b\"", label=1];
+}
+`
+	if buf.String() != expected {
+		t.Errorf("WriteDOT:\n%s\nExpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriteDOTDigraph(t *testing.T) {
+	g := NewGraph(true)
+	attr := NewAttr()
+	attr["name"] = "Bob"
+	attr["age"] = 30
+	g.AddNode(1, attr)
+	g.AddEdge(1, 2, 5, nil)
+	g.AddEdge(2, 1, 1, nil)
+
+	mst := NewGraph(false)
+	mst.AddEdge(2, 1, 1, nil)
+	g.AddEdge(2, 3, 1, nil)
+
+	var buf bytes.Buffer
+	opts := &DOTOptions{NodeAttr: map[string]string{"name": "label"}, Highlight: mst, Color: "blue"}
+	if err := WriteDOT(&buf, g, opts); err != nil {
+		t.Fatalf("WriteDOT: %v", err)
+	}
+
+	expected := `digraph {
+	1 [label=Bob, color=blue];
+	2 [color=blue];
+	3;
+	1 -> 2 [color=blue, penwidth=2];
+	2 -> 1 [color=blue, penwidth=2];
+	2 -> 3;
+}
+`
+	if buf.String() != expected {
+		t.Errorf("WriteDOT:\n%s\nExpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestReadDOT(t *testing.T) {
+	src := `/* sample */
+strict digraph "G" {
+	rankdir=LR; // ignored
+	node [shape=box]
+	a [label="Node " + "A"];
+	a -> b -> c [cost=3, color=red]
+	subgraph cluster_0 {
+		edge [cost=2]
+		node [shape=circle]
+		d; e
+		d -> e
+	}
+	c:n -> {d e}
+	# preprocessor line
+	b -> "quoted \"name\"" [cost=4]
+}`
+	g, ids, err := ReadDOT(strings.NewReader(src), "cost")
+	if err != nil {
+		t.Fatalf("ReadDOT: %v", err)
+	}
+
+	if !g.IsDirected() || g.Len() != 6 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", g.IsDirected(), g.Len())
+	}
+	expected := map[string]uint64{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, `quoted "name"`: 6}
+	for name, id := range expected {
+		if ids[name] != id {
+			t.Errorf("Node %q: %d. Expected %d", name, ids[name], id)
+		}
+	}
+
+	attr, _ := g.Node(1)
+	if attr["label"] != "Node A" || attr["shape"] != "box" {
+		t.Errorf("Unexpected attributes for a: %v", attr)
+	}
+	attr, _ = g.Node(4)
+	if attr["shape"] != "circle" {
+		t.Errorf("Unexpected attributes for d: %v", attr)
+	}
+
+	edge, ok := g.Edge(2, 3)
+	if !ok || edge.Weight != 3 || edge.Attr["color"] != "red" {
+		t.Errorf("Unexpected edge b->c: %v", edge)
+	}
+	if _, ok := edge.Attr["cost"]; ok {
+		t.Errorf("Weight attribute should not be stored in Attr")
+	}
+	if edge, _ := g.Edge(4, 5); edge.Weight != 2 {
+		t.Errorf("Edge d->e weight: %d. Expected 2", edge.Weight)
+	}
+	if edge, _ := g.Edge(3, 5); edge.Weight != 1 {
+		t.Errorf("Edge c->e weight: %d. Expected 1", edge.Weight)
+	}
+	testEdgeExists(t, g, 3, 4, true)
+	testEdgeExists(t, g, 2, 6, true)
+	testEdgeExists(t, g, 2, 1, false)
+}
+
+func TestReadDOTRoundTrip(t *testing.T) {
+	g := sampleGraph()
+	g.AddNode(10, Attr{"name": "Bob", "path": `C:\dir\`, "quote": `say \"hi\"`})
+
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, g, &DOTOptions{Weight: "weight"}); err != nil {
+		t.Fatalf("WriteDOT: %v", err)
+	}
+	read, _, err := ReadDOT(&buf, "weight")
+	if err != nil {
+		t.Fatalf("ReadDOT: %v", err)
+	}
+
+	if read.IsDirected() || read.Len() != g.Len() {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", read.IsDirected(), read.Len())
+	}
+	for _, e := range g.sortedEdges() {
+		testEdgeExists(t, read, e.u, e.v, true)
+	}
+	if attr, _ := read.Node(10); attr["name"] != "Bob" || attr["path"] != `C:\dir\` || attr["quote"] != `say \"hi\"` {
+		t.Errorf("Unexpected attributes for node 10: %v", attr)
+	}
+}
+
+func TestReadDOTErrors(t *testing.T) {
+	for _, src := range []string{
+		"graph { a -> b }",
+		"digraph { a -> b [weight=x] }",
+		"digraph { a -> }",
+		`graph { "a }`,
+		"graph { a -- b",
+	} {
+		if _, _, err := ReadDOT(strings.NewReader(src), "weight"); err == nil {
+			t.Errorf("ReadDOT(%q): Did not get expected error", src)
+		} else if _, ok := err.(*ParseError); !ok {
+			t.Errorf("ReadDOT(%q): Expected *ParseError, got %v", src, err)
+		}
+	}
+}
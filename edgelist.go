@@ -0,0 +1,220 @@
+package grapho
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EdgeListOptions configures the edge list format: one edge per line, with the source
+// node, target node, weight and any number of extra columns, i.e. "src,dst,weight,label".
+// The weight column is optional: edges without it are given a weight of 1.
+type EdgeListOptions struct {
+	Delimiter rune     // Field delimiter (CSV quoting rules apply). If zero, fields are separated by white space
+	Comment   rune     // Lines starting with this character are ignored. Defaults to '#'
+	Header    bool     // Whether the first line holds the column names
+	Columns   []string // Attr keys of the extra columns. Overridden by the header names, if present
+}
+
+func (opts *EdgeListOptions) comment() rune {
+	if opts.Comment == 0 {
+		return '#'
+	}
+	return opts.Comment
+}
+
+// EdgeListReader reads edges from an edge list, one line at a time.
+type EdgeListReader struct {
+	opts    EdgeListOptions
+	csv     *csv.Reader
+	scanner *bufio.Scanner
+	line    int
+	header  bool // whether the header line has been read
+}
+
+// NewEdgeListReader creates an EdgeListReader reading from r. If opts is nil, white space separated
+// fields are expected, with '#' comments and no header (i.e. SNAP datasets).
+func NewEdgeListReader(r io.Reader, opts *EdgeListOptions) *EdgeListReader {
+	er := &EdgeListReader{}
+	if opts != nil {
+		er.opts = *opts
+	}
+
+	if er.opts.Delimiter != 0 {
+		er.csv = csv.NewReader(r)
+		er.csv.Comma = er.opts.Delimiter
+		er.csv.Comment = er.opts.comment()
+		er.csv.FieldsPerRecord = -1
+		er.csv.TrimLeadingSpace = true
+		er.csv.ReuseRecord = true
+	} else {
+		er.scanner = bufio.NewScanner(r)
+		er.scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	}
+	return er
+}
+
+// Line returns the line number of the last record read.
+func (er *EdgeListReader) Line() int {
+	return er.line
+}
+
+// record returns the fields of the next non-empty, non-comment line.
+func (er *EdgeListReader) record() ([]string, error) {
+	if er.csv != nil {
+		fields, err := er.csv.Read()
+		if err != nil {
+			if pe, ok := err.(*csv.ParseError); ok {
+				return nil, &ParseError{pe.Line, pe.Err}
+			}
+			return nil, err
+		}
+		er.line, _ = er.csv.FieldPos(0)
+		return fields, nil
+	}
+
+	for er.scanner.Scan() {
+		er.line++
+		line := strings.TrimSpace(er.scanner.Text())
+		if line == "" || strings.HasPrefix(line, string(er.opts.comment())) {
+			continue
+		}
+		return strings.Fields(line), nil
+	}
+	if err := er.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// Read returns the next edge in the list. At the end of the input, the returned error is io.EOF.
+// Values of the extra columns are stored in attr as strings, under their column name.
+// Columns without a name are ignored. Malformed lines are reported with a *ParseError.
+func (er *EdgeListReader) Read() (u, v uint64, weight int, attr Attr, err error) {
+	if er.opts.Header && !er.header {
+		er.header = true
+		fields, err := er.record()
+		if err != nil {
+			return 0, 0, 0, nil, err
+		}
+		if len(fields) > 3 {
+			er.opts.Columns = append([]string(nil), fields[3:]...)
+		} else {
+			er.opts.Columns = nil
+		}
+	}
+
+	fields, err := er.record()
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	if len(fields) < 2 {
+		return 0, 0, 0, nil, &ParseError{er.line, fmt.Errorf("Expected at least 2 fields, found %d", len(fields))}
+	}
+
+	if u, err = strconv.ParseUint(strings.TrimSpace(fields[0]), 10, 64); err != nil {
+		return 0, 0, 0, nil, &ParseError{er.line, fmt.Errorf("Invalid source node %q", fields[0])}
+	}
+	if v, err = strconv.ParseUint(strings.TrimSpace(fields[1]), 10, 64); err != nil {
+		return 0, 0, 0, nil, &ParseError{er.line, fmt.Errorf("Invalid target node %q", fields[1])}
+	}
+	weight = 1
+	if len(fields) > 2 {
+		if weight, err = strconv.Atoi(strings.TrimSpace(fields[2])); err != nil {
+			return 0, 0, 0, nil, &ParseError{er.line, fmt.Errorf("Invalid weight %q", fields[2])}
+		}
+	}
+
+	attr = NewAttr()
+	for i, name := range er.opts.Columns {
+		if 3+i < len(fields) {
+			attr[name] = fields[3+i]
+		}
+	}
+	return u, v, weight, attr, nil
+}
+
+// ReadEdgeList builds a Graph from an edge list, reading it line by line.
+// See NewEdgeListReader and EdgeListReader.Read for details on the format.
+func ReadEdgeList(r io.Reader, directed bool, opts *EdgeListOptions) (*Graph, error) {
+	g := NewGraph(directed)
+	er := NewEdgeListReader(r, opts)
+	for {
+		u, v, weight, attr, err := er.Read()
+		if err == io.EOF {
+			return g, nil
+		} else if err != nil {
+			return nil, err
+		}
+		g.AddEdge(u, v, weight, attr)
+	}
+}
+
+// WriteEdgeList writes the edges of the Graph as an edge list, ordered by source and target node.
+// In undirected graphs, every edge is written once. Extra columns hold the edge Attr values
+// for the keys in opts.Columns (empty if missing). Isolated nodes are not written.
+// As white space separated fields cannot be quoted, values (and column names) holding white space,
+// or missing values followed by non-empty ones, are rejected unless opts.Delimiter is set.
+func WriteEdgeList(w io.Writer, g *Graph, opts *EdgeListOptions) error {
+	if opts == nil {
+		opts = &EdgeListOptions{}
+	}
+
+	var cw *csv.Writer
+	bw := bufio.NewWriter(w)
+	write := func(fields []string) error {
+		// Trailing empty values are read back as missing, but any other one would shift the columns
+		n := len(fields)
+		for n > 0 && fields[n-1] == "" {
+			n--
+		}
+		for i, field := range fields[:n] {
+			if field == "" || strings.IndexFunc(field, unicode.IsSpace) >= 0 {
+				return fmt.Errorf("Value %q of column %d cannot be written without a delimiter", field, i+1)
+			}
+		}
+		bw.WriteString(strings.Join(fields[:n], " "))
+		return bw.WriteByte('\n')
+	}
+	if opts.Delimiter != 0 {
+		cw = csv.NewWriter(bw)
+		cw.Comma = opts.Delimiter
+		write = func(fields []string) error { return cw.Write(fields) }
+	}
+
+	fields := make([]string, 3+len(opts.Columns))
+	if opts.Header {
+		copy(fields, []string{"src", "dst", "weight"})
+		copy(fields[3:], opts.Columns)
+		if err := write(fields); err != nil {
+			return err
+		}
+	}
+
+	for _, edge := range g.sortedEdges() {
+		fields[0] = strconv.FormatUint(edge.u, 10)
+		fields[1] = strconv.FormatUint(edge.v, 10)
+		fields[2] = strconv.Itoa(edge.Weight)
+		for i, name := range opts.Columns {
+			fields[3+i] = ""
+			if value, ok := edge.Attr[name]; ok {
+				fields[3+i] = fmt.Sprint(value)
+			}
+		}
+		if err := write(fields); err != nil {
+			return err
+		}
+	}
+
+	if cw != nil {
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,148 @@
+package grapho
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadEdgeList(t *testing.T) {
+	src := `# Directed graph (each unordered pair of nodes is saved once)
+# FromNodeId	ToNodeId
+1	2
+2	3	5
+
+# trailing comment
+3 1
+`
+	g, err := ReadEdgeList(strings.NewReader(src), true, nil)
+	if err != nil {
+		t.Fatalf("ReadEdgeList: %v", err)
+	}
+	if g.Len() != 3 {
+		t.Errorf("Expected size %d, got %d", 3, g.Len())
+	}
+	if edge, ok := g.Edge(2, 3); !ok || edge.Weight != 5 {
+		t.Errorf("Unexpected edge 2-3: %v", edge)
+	}
+	if edge, ok := g.Edge(3, 1); !ok || edge.Weight != 1 {
+		t.Errorf("Unexpected edge 3-1: %v", edge)
+	}
+	testEdgeExists(t, g, 2, 1, false)
+}
+
+func TestReadEdgeListCSV(t *testing.T) {
+	src := `src,dst,weight,label,since
+1,2,3,"friend, close",2010
+; comment
+2,3,4,coworker
+`
+	er := NewEdgeListReader(strings.NewReader(src), &EdgeListOptions{Delimiter: ',', Comment: ';', Header: true})
+
+	u, v, weight, attr, err := er.Read()
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if u != 1 || v != 2 || weight != 3 || attr["label"] != "friend, close" || attr["since"] != "2010" {
+		t.Errorf("Unexpected edge %d-%d (%d): %v", u, v, weight, attr)
+	}
+	if er.Line() != 2 {
+		t.Errorf("Line: %d. Expected 2", er.Line())
+	}
+
+	u, v, weight, attr, err = er.Read()
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if u != 2 || v != 3 || weight != 4 || attr["label"] != "coworker" || len(attr) != 1 {
+		t.Errorf("Unexpected edge %d-%d (%d): %v", u, v, weight, attr)
+	}
+	if er.Line() != 4 {
+		t.Errorf("Line: %d. Expected 4", er.Line())
+	}
+
+	if _, _, _, _, err = er.Read(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestReadEdgeListErrors(t *testing.T) {
+	for src, line := range map[string]int{
+		"1 2\n# comment\n3 x\n": 3,
+		"1 2 1.5\n":             1,
+		"1\n":                   1,
+	} {
+		_, err := ReadEdgeList(strings.NewReader(src), false, nil)
+		if pe, ok := err.(*ParseError); !ok || pe.Line != line {
+			t.Errorf("ReadEdgeList(%q): Expected error on line %d, got %v", src, line, err)
+		}
+	}
+
+	_, err := ReadEdgeList(strings.NewReader("1,2\n3,\"4\n"), false, &EdgeListOptions{Delimiter: ','})
+	if _, ok := err.(*ParseError); !ok {
+		t.Errorf("Expected *ParseError, got %v", err)
+	}
+}
+
+func TestWriteEdgeList(t *testing.T) {
+	g := NewGraph(false)
+	g.AddEdge(2, 1, 3, Attr{"label": "a, b"})
+	g.AddEdge(2, 3, 1, nil)
+
+	var buf bytes.Buffer
+	opts := &EdgeListOptions{Delimiter: ',', Header: true, Columns: []string{"label"}}
+	if err := WriteEdgeList(&buf, g, opts); err != nil {
+		t.Fatalf("WriteEdgeList: %v", err)
+	}
+	expected := "src,dst,weight,label\n1,2,3,\"a, b\"\n2,3,1,\n"
+	if buf.String() != expected {
+		t.Errorf("WriteEdgeList: %q. Expected %q", buf.String(), expected)
+	}
+
+	read, err := ReadEdgeList(&buf, false, &EdgeListOptions{Delimiter: ',', Header: true})
+	if err != nil {
+		t.Fatalf("ReadEdgeList: %v", err)
+	}
+	if edge, ok := read.Edge(1, 2); !ok || edge.Weight != 3 || edge.Attr["label"] != "a, b" {
+		t.Errorf("Unexpected edge 1-2: %v", edge)
+	}
+
+	buf.Reset()
+	if err := WriteEdgeList(&buf, g, nil); err != nil {
+		t.Fatalf("WriteEdgeList: %v", err)
+	}
+	if buf.String() != "1 2 3\n2 3 1\n" {
+		t.Errorf("WriteEdgeList: %q", buf.String())
+	}
+	// White space separated values round trip, as long as they hold no white space
+	g.AddEdge(3, 4, 2, Attr{"label": "c", "color": "red"})
+	opts = &EdgeListOptions{Columns: []string{"color", "label"}}
+	buf.Reset()
+	if err := WriteEdgeList(&buf, g, opts); err == nil {
+		t.Errorf("WriteEdgeList: Did not get expected error for value %q", "a, b")
+	}
+	g.AddEdge(2, 1, 3, Attr{"label": "a"})
+	buf.Reset()
+	if err := WriteEdgeList(&buf, g, opts); err == nil {
+		t.Errorf("WriteEdgeList: Did not get expected error for a missing value before %q", "a")
+	}
+	g.AddEdge(2, 1, 3, Attr{"color": "blue"})
+	buf.Reset()
+	if err := WriteEdgeList(&buf, g, opts); err != nil {
+		t.Fatalf("WriteEdgeList: %v", err)
+	}
+	if buf.String() != "1 2 3 blue\n2 3 1\n3 4 2 red c\n" {
+		t.Errorf("WriteEdgeList: %q", buf.String())
+	}
+	read, err = ReadEdgeList(&buf, false, opts)
+	if err != nil {
+		t.Fatalf("ReadEdgeList: %v", err)
+	}
+	if edge, _ := read.Edge(1, 2); edge.Attr["color"] != "blue" || len(edge.Attr) != 1 {
+		t.Errorf("Unexpected edge 1-2: %v", edge)
+	}
+	if edge, _ := read.Edge(4, 3); edge.Attr["color"] != "red" || edge.Attr["label"] != "c" {
+		t.Errorf("Unexpected edge 3-4: %v", edge)
+	}
+}
@@ -0,0 +1,171 @@
+// Graph Exchange XML Format (GEXF)
+// https://gexf.net/schema.html
+
+package grapho
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+const gexfNamespace = "http://gexf.net/1.3"
+
+type gexfDoc struct {
+	XMLName xml.Name  `xml:"gexf"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+	Mode            string           `xml:"mode,attr"`
+	Attributes      []gexfAttributes `xml:"attributes"`
+	Nodes           []gexfNode       `xml:"nodes>node"`
+	Edges           []gexfEdge       `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID        string         `xml:"id,attr"`
+	Label     string         `xml:"label,attr"`
+	AttValues *gexfAttValues `xml:"attvalues"`
+}
+
+type gexfEdge struct {
+	ID        string         `xml:"id,attr"`
+	Source    string         `xml:"source,attr"`
+	Target    string         `xml:"target,attr"`
+	Weight    int            `xml:"weight,attr"`
+	AttValues *gexfAttValues `xml:"attvalues"`
+}
+
+type gexfAttValues struct {
+	Values []gexfAttValue `xml:"attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// gexfTypes binds the attribute value types to their GEXF name.
+var gexfTypes = map[string]string{
+	kindBoolean: "boolean",
+	kindInt:     "integer",
+	kindLong:    "long",
+	kindFloat:   "float",
+	kindDouble:  "double",
+	kindString:  "string",
+}
+
+// WriteGEXF writes the Graph as a GEXF 1.3 document, i.e. to be visualized in Gephi. Nodes are
+// labelled with their LabelKey attribute (or their node id, if missing), and edges carry Edge.Weight
+// as their weight. Any other node and edge attributes are declared as GEXF attributes, with their type
+// inferred from the values, as in WriteGraphML. Nodes and edges are ordered by node id.
+func WriteGEXF(w io.Writer, g *Graph) error {
+	nodes := g.sortedNodeIDs()
+	edges := g.sortedEdges()
+
+	nodeAttrs := make([]Attr, len(nodes))
+	for i, node := range nodes {
+		attr := NewAttr()
+		for k, v := range g.nodes[node] {
+			if k != LabelKey {
+				attr[k] = v
+			}
+		}
+		nodeAttrs[i] = attr
+	}
+	edgeAttrs := make([]Attr, len(edges))
+	for i, edge := range edges {
+		edgeAttrs[i] = edge.Attr
+	}
+
+	nodeKinds, err := attrKinds(nodeAttrs)
+	if err != nil {
+		return err
+	}
+	edgeKinds, err := attrKinds(edgeAttrs)
+	if err != nil {
+		return err
+	}
+
+	graph := gexfGraph{DefaultEdgeType: "undirected", Mode: "static"}
+	if g.directed {
+		graph.DefaultEdgeType = "directed"
+	}
+
+	// declare attributes, binding their names to attribute ids
+	declare := func(class string, kinds map[string]string) map[string]string {
+		ids := make(map[string]string, len(kinds))
+		if len(kinds) == 0 {
+			return ids
+		}
+		attrs := gexfAttributes{Class: class}
+		for _, name := range sortedKinds(kinds) {
+			id := fmt.Sprint(len(attrs.Attributes))
+			attrs.Attributes = append(attrs.Attributes, gexfAttribute{id, name, gexfTypes[kinds[name]]})
+			ids[name] = id
+		}
+		graph.Attributes = append(graph.Attributes, attrs)
+		return ids
+	}
+	nodeIDs := declare("node", nodeKinds)
+	edgeIDs := declare("edge", edgeKinds)
+
+	// values returns the attribute values of a node/edge, or nil if there are none
+	values := func(attr Attr, ids map[string]string) *gexfAttValues {
+		if len(attr) == 0 {
+			return nil
+		}
+		values := &gexfAttValues{}
+		for _, k := range sortedKeys(attr) {
+			values.Values = append(values.Values, gexfAttValue{ids[k], fmt.Sprint(attr[k])})
+		}
+		return values
+	}
+
+	for i, node := range nodes {
+		label := fmt.Sprint(node)
+		if l, ok := g.nodes[node][LabelKey]; ok {
+			label = fmt.Sprint(l)
+		}
+		graph.Nodes = append(graph.Nodes, gexfNode{
+			ID:        fmt.Sprint(node),
+			Label:     label,
+			AttValues: values(nodeAttrs[i], nodeIDs),
+		})
+	}
+	for i, edge := range edges {
+		graph.Edges = append(graph.Edges, gexfEdge{
+			ID:        fmt.Sprint(i),
+			Source:    fmt.Sprint(edge.u),
+			Target:    fmt.Sprint(edge.v),
+			Weight:    edge.Weight,
+			AttValues: values(edge.Attr, edgeIDs),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(gexfDoc{Xmlns: gexfNamespace, Version: "1.3", Graph: graph}); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,62 @@
+package grapho
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteGEXF(t *testing.T) {
+	g := NewGraph(true)
+	g.AddNode(1, Attr{LabelKey: "A", "size": 2.5})
+	g.AddNode(2, Attr{"size": 1, "core": true})
+	g.AddEdge(1, 2, 3, Attr{"kind": "import"})
+	g.AddEdge(2, 3, 1, nil)
+
+	var buf bytes.Buffer
+	if err := WriteGEXF(&buf, g); err != nil {
+		t.Fatalf("WriteGEXF: %v", err)
+	}
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <graph defaultedgetype="directed" mode="static">
+    <attributes class="node">
+      <attribute id="0" title="core" type="boolean"></attribute>
+      <attribute id="1" title="size" type="double"></attribute>
+    </attributes>
+    <attributes class="edge">
+      <attribute id="0" title="kind" type="string"></attribute>
+    </attributes>
+    <nodes>
+      <node id="1" label="A">
+        <attvalues>
+          <attvalue for="1" value="2.5"></attvalue>
+        </attvalues>
+      </node>
+      <node id="2" label="2">
+        <attvalues>
+          <attvalue for="0" value="true"></attvalue>
+          <attvalue for="1" value="1"></attvalue>
+        </attvalues>
+      </node>
+      <node id="3" label="3"></node>
+    </nodes>
+    <edges>
+      <edge id="0" source="1" target="2" weight="3">
+        <attvalues>
+          <attvalue for="0" value="import"></attvalue>
+        </attvalues>
+      </edge>
+      <edge id="1" source="2" target="3" weight="1"></edge>
+    </edges>
+  </graph>
+</gexf>
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+
+	g.AddNode(4, Attr{"size": "big"})
+	if err := WriteGEXF(&buf, g); err == nil {
+		t.Error("Expected error with mixed attribute types")
+	}
+}
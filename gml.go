@@ -0,0 +1,394 @@
+// Graph Modelling Language (GML)
+// http://www.fim.uni-passau.de/fileadmin/files/lehrstuhl/brandenburg/projekte/gml/gml-technical-report.pdf
+
+package grapho
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// gmlPair is a key-value pair of a GML list. Values are int, float64, string or gmlList.
+type gmlPair struct {
+	key   string
+	value interface{}
+	line  int
+}
+
+type gmlList []gmlPair
+
+// gmlParser parses GML source into nested lists.
+type gmlParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *gmlParser) errorf(format string, args ...interface{}) error {
+	return &ParseError{p.line, fmt.Errorf(format, args...)}
+}
+
+// token returns the next token, or an empty string at the end of the input.
+func (p *gmlParser) token() (string, error) {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == '"':
+			end := strings.IndexByte(p.src[p.pos+1:], '"')
+			if end < 0 {
+				return "", p.errorf("Unterminated string")
+			}
+			tok := p.src[p.pos : p.pos+end+2]
+			p.line += strings.Count(tok, "\n")
+			p.pos += end + 2
+			return tok, nil
+		case c == '[' || c == ']':
+			p.pos++
+			return string(c), nil
+		default:
+			start := p.pos
+			for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n[]\"", rune(p.src[p.pos])) {
+				p.pos++
+			}
+			return p.src[start:p.pos], nil
+		}
+	}
+	return "", nil
+}
+
+// list parses key-value pairs until the closing bracket (or the end of the input, at the top level).
+func (p *gmlParser) list(top bool) (gmlList, error) {
+	var list gmlList
+	for {
+		key, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case key == "" && top:
+			return list, nil
+		case key == "":
+			return nil, p.errorf("Unexpected end of input, expected ']'")
+		case key == "]" && !top:
+			return list, nil
+		case !gmlKey(key):
+			return nil, p.errorf("Invalid key %q", key)
+		}
+
+		line := p.line
+		tok, err := p.token()
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+		switch {
+		case tok == "[":
+			if value, err = p.list(false); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(tok, `"`):
+			value = html.UnescapeString(tok[1 : len(tok)-1])
+		case tok == "" || tok == "]":
+			return nil, p.errorf("Missing value for key %q", key)
+		default:
+			if i, err := strconv.Atoi(tok); err == nil {
+				value = i
+			} else if f, err := strconv.ParseFloat(tok, 64); err == nil {
+				value = f
+			} else {
+				return nil, p.errorf("Invalid value %q", tok)
+			}
+		}
+		list = append(list, gmlPair{key, value, line})
+	}
+}
+
+// gmlKey returns whether s is a valid GML key.
+func gmlKey(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// attr converts a GML list into an Attr, skipping the given keys.
+// Repeated keys are gathered in a []interface{} value.
+func (l gmlList) attr(skip ...string) Attr {
+	attr := NewAttr()
+	for _, pair := range l {
+		if containsString(skip, pair.key) {
+			continue
+		}
+		value := pair.value
+		if list, ok := value.(gmlList); ok {
+			value = list.attr()
+		}
+
+		switch current := attr[pair.key].(type) {
+		case nil:
+			attr[pair.key] = value
+		case []interface{}:
+			attr[pair.key] = append(current, value)
+		default:
+			attr[pair.key] = []interface{}{current, value}
+		}
+	}
+	return attr
+}
+
+// id returns the value of the given key as a node id.
+func (pair gmlPair) id() (uint64, error) {
+	id, ok := pair.value.(int)
+	if !ok || id < 0 {
+		return 0, &ParseError{pair.line, fmt.Errorf("Invalid node id %v", pair.value)}
+	}
+	return uint64(id), nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadGML builds a Graph from a GML document. The graph is directed if its "directed" key is 1.
+// Nodes are identified by their (non-negative) "id" key, and edges by their "source" and "target" keys.
+// Any other node and edge keys, including "label", are stored in Attr: integers as int, reals as float64,
+// strings as string and nested lists as Attr. Repeated keys are gathered in a []interface{} value.
+// The edge "weight" key, if an integer, is read as Edge.Weight instead. Edges without it, or with a
+// non-integer weight (which is kept in Attr), are given a weight of 1.
+func ReadGML(r io.Reader) (*Graph, error) {
+	src, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &gmlParser{src: string(src), line: 1}
+	doc, err := p.list(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var graph gmlList
+	for _, pair := range doc {
+		if pair.key == "graph" {
+			if graph, _ = pair.value.(gmlList); graph == nil {
+				return nil, &ParseError{pair.line, errors.New("Expected a list for key 'graph'")}
+			}
+			break
+		}
+	}
+	if graph == nil {
+		return nil, errors.New("Missing 'graph' list")
+	}
+
+	directed := false
+	for _, pair := range graph {
+		if pair.key == "directed" {
+			directed = pair.value == 1
+		}
+	}
+
+	g := NewGraph(directed)
+	for _, pair := range graph {
+		list, ok := pair.value.(gmlList)
+		if !ok || pair.key != "node" && pair.key != "edge" {
+			continue
+		}
+
+		if pair.key == "node" {
+			var id *uint64
+			for _, p := range list {
+				if p.key == "id" {
+					n, err := p.id()
+					if err != nil {
+						return nil, err
+					}
+					id = &n
+				}
+			}
+			if id == nil {
+				return nil, &ParseError{pair.line, errors.New("Node without id")}
+			}
+			// the node may have been created by a previous edge
+			if attr := list.attr("id"); !g.UpdateNodeAttr(*id, attr) {
+				g.AddNode(*id, attr)
+			}
+			continue
+		}
+
+		var source, target *uint64
+		weight, integer := 1, false
+		for _, p := range list {
+			switch p.key {
+			case "source", "target":
+				n, err := p.id()
+				if err != nil {
+					return nil, err
+				}
+				if p.key == "source" {
+					source = &n
+				} else {
+					target = &n
+				}
+			case "weight":
+				if w, ok := p.value.(int); ok {
+					weight = w
+					integer = true
+				}
+			}
+		}
+		if source == nil || target == nil {
+			return nil, &ParseError{pair.line, errors.New("Edge without source or target")}
+		}
+		reserved := []string{"source", "target"}
+		if integer {
+			reserved = append(reserved, "weight")
+		}
+		g.AddEdge(*source, *target, weight, list.attr(reserved...))
+	}
+
+	return g, nil
+}
+
+// WriteGML writes the Graph as a GML document. Attr values are written as GML integers (integer types
+// and bool), reals (float types), lists (Attr, and []interface{} as repeated keys) or strings (anything else).
+// Attr keys must be valid GML keys, and cannot clash with the reserved "id" (nodes) and "source",
+// "target" and "weight" (edges) keys. The only exception is a non-integer edge "weight" Attr value, as read
+// by ReadGML, which is written instead of Edge.Weight if this is 1. Node ids must not exceed math.MaxInt64.
+func WriteGML(w io.Writer, g *Graph) error {
+	bw := bufio.NewWriter(w)
+
+	directed := 0
+	if g.directed {
+		directed = 1
+	}
+	fmt.Fprintf(bw, "graph [\n  directed %d\n", directed)
+
+	for _, node := range g.sortedNodeIDs() {
+		if node > math.MaxInt64 {
+			return fmt.Errorf("Node id %d out of range", node)
+		}
+		fmt.Fprintf(bw, "  node [\n    id %d\n", node)
+		if err := writeGMLAttr(bw, g.nodes[node], "    ", "id"); err != nil {
+			return fmt.Errorf("Node %d: %v", node, err)
+		}
+		bw.WriteString("  ]\n")
+	}
+
+	for _, edge := range g.sortedEdges() {
+		fmt.Fprintf(bw, "  edge [\n    source %d\n    target %d\n", edge.u, edge.v)
+		reserved := []string{"source", "target", "weight"}
+		if gmlRealWeight(edge.Attr) && edge.Weight == 1 {
+			reserved = reserved[:2] // written along with the other attributes
+		} else {
+			fmt.Fprintf(bw, "    weight %d\n", edge.Weight)
+		}
+		if err := writeGMLAttr(bw, edge.Attr, "    ", reserved...); err != nil {
+			return fmt.Errorf("Edge %d-%d: %v", edge.u, edge.v, err)
+		}
+		bw.WriteString("  ]\n")
+	}
+
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
+
+// gmlRealWeight returns whether the attribute set holds a non-integer "weight" value, as read by ReadGML.
+func gmlRealWeight(attr Attr) bool {
+	v, ok := attr["weight"]
+	if !ok {
+		return false
+	}
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint8, uint16, uint32, uint, uint64, bool:
+		return false
+	}
+	return true
+}
+
+// writeGMLAttr writes the key-value pairs of the given attribute set, sorted by key.
+func writeGMLAttr(w *bufio.Writer, attr Attr, indent string, reserved ...string) error {
+	for _, k := range sortedKeys(attr) {
+		if !gmlKey(k) {
+			return fmt.Errorf("Invalid GML key %q", k)
+		}
+		if containsString(reserved, k) {
+			return fmt.Errorf("Attribute %q clashes with a reserved key", k)
+		}
+
+		values, ok := attr[k].([]interface{})
+		if !ok {
+			values = []interface{}{attr[k]}
+		}
+		for _, v := range values {
+			if err := writeGMLValue(w, k, v, indent); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeGMLValue(w *bufio.Writer, key string, value interface{}, indent string) error {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint8, uint16, uint32, uint, uint64:
+		fmt.Fprintf(w, "%s%s %d\n", indent, key, v)
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		fmt.Fprintf(w, "%s%s %d\n", indent, key, b)
+	case float32, float64:
+		f := toFloat64(v)
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return fmt.Errorf("Invalid real value %v for key %q", f, key)
+		}
+		s := strconv.FormatFloat(f, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+		fmt.Fprintf(w, "%s%s %s\n", indent, key, s)
+	case Attr:
+		fmt.Fprintf(w, "%s%s [\n", indent, key)
+		if err := writeGMLAttr(w, v, indent+"  "); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s]\n", indent)
+	default:
+		s := html.EscapeString(fmt.Sprint(v))
+		fmt.Fprintf(w, "%s%s \"%s\"\n", indent, key, s)
+	}
+	return nil
+}
+
+// toFloat64 converts a float32 or float64 value to float64.
+func toFloat64(v interface{}) float64 {
+	if f, ok := v.(float32); ok {
+		return float64(f)
+	}
+	return v.(float64)
+}
@@ -0,0 +1,155 @@
+package grapho
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReadGML(t *testing.T) {
+	src := `Creator "someone"
+graph
+[
+  comment "sample"
+  directed 1
+  # comment line
+  node [ id 1 label "A &quot;quoted&quot; node" graphics [ x 1.5 y -2 ] ]
+  node [ id 2 label "B" tag "x" tag "y" ]
+  edge [ source 1 target 2 weight 4 value 2.5 ]
+  edge [ source 2 target 3 ]
+  node [ id 3 ]
+]`
+	g, err := ReadGML(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadGML: %v", err)
+	}
+	if !g.IsDirected() || g.Len() != 3 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", g.IsDirected(), g.Len())
+	}
+
+	attr, _ := g.Node(1)
+	if attr[LabelKey] != `A "quoted" node` {
+		t.Errorf("Unexpected label for node 1: %v", attr[LabelKey])
+	}
+	graphics, ok := attr["graphics"].(Attr)
+	if !ok || graphics["x"] != 1.5 || graphics["y"] != -2 {
+		t.Errorf("Unexpected nested attributes for node 1: %v", attr["graphics"])
+	}
+	attr, _ = g.Node(2)
+	if tags, ok := attr["tag"].([]interface{}); !ok || len(tags) != 2 || tags[1] != "y" {
+		t.Errorf("Unexpected repeated attributes for node 2: %v", attr["tag"])
+	}
+
+	edge, ok := g.Edge(1, 2)
+	if !ok || edge.Weight != 4 || edge.Attr["value"] != 2.5 {
+		t.Errorf("Unexpected edge 1-2: %v", edge)
+	}
+	if edge, _ := g.Edge(2, 3); edge.Weight != 1 {
+		t.Errorf("Edge 2-3 weight: %d. Expected 1", edge.Weight)
+	}
+
+	for src, line := range map[string]int{
+		"graph [ node [ label \"x\" ] ]":                    1,
+		"graph [\n node [ id -1 ] ]":                        2,
+		"graph [\n edge [ source 1 ] ]":                     2,
+		"graph [\n edge [ source 1 target 2\n weight x ] ]": 3,
+		"graph [ node [ id 1 ]":                             1,
+		"graph [ 1x 2 ]":                                    1,
+	} {
+		_, err := ReadGML(strings.NewReader(src))
+		if pe, ok := err.(*ParseError); !ok || pe.Line != line {
+			t.Errorf("ReadGML(%q): Expected error on line %d, got %v", src, line, err)
+		}
+	}
+}
+
+func TestWriteGML(t *testing.T) {
+	g := NewGraph(false)
+	g.AddNode(1, Attr{LabelKey: `say "hi"`, "graphics": Attr{"x": 1.0, "w": float32(0.5)}, "ok": true})
+	g.AddEdge(2, 1, 3, Attr{"tag": []interface{}{"a", 2}})
+
+	var buf bytes.Buffer
+	if err := WriteGML(&buf, g); err != nil {
+		t.Fatalf("WriteGML: %v", err)
+	}
+	expected := `graph [
+  directed 0
+  node [
+    id 1
+    graphics [
+      w 0.5
+      x 1.0
+    ]
+    label "say &#34;hi&#34;"
+    ok 1
+  ]
+  node [
+    id 2
+  ]
+  edge [
+    source 1
+    target 2
+    weight 3
+    tag "a"
+    tag 2
+  ]
+]
+`
+	if buf.String() != expected {
+		t.Errorf("WriteGML:\n%s\nExpected:\n%s", buf.String(), expected)
+	}
+
+	read, err := ReadGML(&buf)
+	if err != nil {
+		t.Fatalf("ReadGML: %v", err)
+	}
+	if attr, _ := read.Node(1); attr[LabelKey] != `say "hi"` {
+		t.Errorf("Unexpected label for node 1: %v", attr[LabelKey])
+	}
+	if edge, ok := read.Edge(1, 2); !ok || edge.Weight != 3 {
+		t.Errorf("Unexpected edge 1-2: %v", edge)
+	}
+
+	g.AddNode(3, Attr{"id": 4})
+	if err := WriteGML(&buf, g); err == nil {
+		t.Errorf("WriteGML: Did not get expected error with reserved key")
+	}
+
+	g = NewGraph(false)
+	g.AddNode(math.MaxInt64+1, nil)
+	if err := WriteGML(&buf, g); err == nil {
+		t.Errorf("WriteGML: Did not get expected error with node id %d", uint64(math.MaxInt64+1))
+	}
+}
+
+func TestGMLRealWeight(t *testing.T) {
+	g, err := ReadGML(strings.NewReader("graph [ edge [ source 1 target 2 weight 1.5 ] ]"))
+	if err != nil {
+		t.Fatalf("ReadGML: %v", err)
+	}
+	edge, ok := g.Edge(1, 2)
+	if !ok || edge.Weight != 1 || edge.Attr["weight"] != 1.5 {
+		t.Fatalf("Unexpected edge 1-2: %v", edge)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteGML(&buf, g); err != nil {
+		t.Fatalf("WriteGML: %v", err)
+	}
+	if strings.Count(buf.String(), "weight") != 1 {
+		t.Errorf("WriteGML: Unexpected output:\n%s", buf.String())
+	}
+	read, err := ReadGML(&buf)
+	if err != nil {
+		t.Fatalf("ReadGML: %v", err)
+	}
+	if edge, ok := read.Edge(1, 2); !ok || edge.Weight != 1 || edge.Attr["weight"] != 1.5 {
+		t.Errorf("Unexpected edge 1-2 after round trip: %v", edge)
+	}
+
+	g.AddEdge(1, 2, 2, Attr{"weight": 1.5})
+	if err := WriteGML(&buf, g); err == nil {
+		t.Errorf("WriteGML: Did not get expected error with both weights")
+	}
+}
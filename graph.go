@@ -57,14 +57,51 @@ func (g *Graph) Len() int {
 func (g *Graph) IsDirected() bool { return g.directed }
 
 // AddNode adds the given node to the Graph. If the node
-// already exists, it will override its attributes.
+// already exists, it will override its attributes, keeping its edges.
 func (g *Graph) AddNode(node uint64, attr Attr) {
 	if attr == nil {
 		attr = NewAttr()
 	}
 
 	g.nodes[node] = attr
-	g.edges[node] = make(map[uint64]*Edge)
+	if _, ok := g.edges[node]; !ok {
+		g.edges[node] = make(map[uint64]*Edge)
+	}
+}
+
+// AddNodeIfAbsent adds the given node to the Graph, only if it does not exist yet.
+// It returns true if the node was added, false if it was already present.
+func (g *Graph) AddNodeIfAbsent(node uint64, attr Attr) bool {
+	if _, ok := g.nodes[node]; ok {
+		return false
+	}
+	g.AddNode(node, attr)
+	return true
+}
+
+// SetNodeAttr sets a single attribute of an existing node.
+// It returns false if the node was not found.
+func (g *Graph) SetNodeAttr(node uint64, key string, value interface{}) bool {
+	attr, ok := g.nodes[node]
+	if !ok {
+		return false
+	}
+	attr[key] = value
+	return true
+}
+
+// UpdateNodeAttr merges the given attributes into the ones of an existing node,
+// overriding the values of any key already present.
+// It returns false if the node was not found.
+func (g *Graph) UpdateNodeAttr(node uint64, attr Attr) bool {
+	current, ok := g.nodes[node]
+	if !ok {
+		return false
+	}
+	for k, v := range attr {
+		current[k] = v
+	}
+	return true
 }
 
 // DeleteNode removes a node entry from the Graph.
@@ -0,0 +1,496 @@
+// graph6, sparse6 and digraph6 formats
+// http://users.cecs.anu.edu.au/~bdm/data/formats.txt
+
+package grapho
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Optional headers of graph6, sparse6 and digraph6 files.
+const (
+	graph6Header   = ">>graph6<<"
+	sparse6Header  = ">>sparse6<<"
+	digraph6Header = ">>digraph6<<"
+)
+
+// maxGraph6Nodes is the largest number of nodes these formats can represent.
+const maxGraph6Nodes = 1<<36 - 1
+
+// maxSparse6Isolated is the largest number of nodes a sparse6 string may declare beyond
+// those its edges could reference. Unlike graph6, their number is not bounded by the input length.
+const maxSparse6Isolated = 1 << 20
+
+// graph6Writer packs bits in groups of 6, each one written as a printable byte (adding 63).
+type graph6Writer struct {
+	buf   []byte
+	word  byte
+	nbits uint
+}
+
+func (w *graph6Writer) writeSize(n int) {
+	switch {
+	case n <= 62:
+		w.buf = append(w.buf, byte(n+63))
+	case n <= 258047:
+		w.buf = append(w.buf, 126)
+		w.writeBits(uint64(n), 18)
+	default:
+		w.buf = append(w.buf, 126, 126)
+		w.writeBits(uint64(n), 36)
+	}
+}
+
+func (w *graph6Writer) writeBit(b bool) {
+	w.word <<= 1
+	if b {
+		w.word |= 1
+	}
+	w.nbits++
+	if w.nbits == 6 {
+		w.buf = append(w.buf, w.word+63)
+		w.word, w.nbits = 0, 0
+	}
+}
+
+// writeBits writes the k lowest bits of x, most significant first.
+func (w *graph6Writer) writeBits(x uint64, k uint) {
+	for i := k; i > 0; i-- {
+		w.writeBit(x>>(i-1)&1 == 1)
+	}
+}
+
+// pad returns the number of bits needed to complete the last byte.
+func (w *graph6Writer) pad() uint {
+	return (6 - w.nbits) % 6
+}
+
+func (w *graph6Writer) String() string {
+	for w.nbits != 0 {
+		w.writeBit(false)
+	}
+	return string(w.buf)
+}
+
+// graph6Reader unpacks the bits written by a graph6Writer.
+type graph6Reader struct {
+	src   string
+	pos   int
+	word  byte
+	nbits uint
+}
+
+func (r *graph6Reader) readSize() (int, error) {
+	if r.pos >= len(r.src) {
+		return 0, errors.New("Missing number of nodes")
+	}
+	if r.src[r.pos] != 126 {
+		n, err := r.readByte()
+		return int(n), err
+	}
+	r.pos++
+
+	k := uint(18)
+	if r.pos < len(r.src) && r.src[r.pos] == 126 {
+		r.pos++
+		k = 36
+	}
+	var n uint64
+	for i := uint(0); i < k; i += 6 {
+		b, err := r.readByte()
+		if err != nil {
+			return 0, errors.New("Truncated number of nodes")
+		}
+		n = n<<6 | uint64(b)
+	}
+	return int(n), nil
+}
+
+func (r *graph6Reader) readByte() (byte, error) {
+	if r.pos >= len(r.src) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	c := r.src[r.pos]
+	if c < 63 || c > 126 {
+		return 0, fmt.Errorf("Invalid character %q", c)
+	}
+	r.pos++
+	return c - 63, nil
+}
+
+func (r *graph6Reader) readBit() (bool, error) {
+	if r.nbits == 0 {
+		b, err := r.readByte()
+		if err != nil {
+			return false, err
+		}
+		r.word, r.nbits = b, 6
+	}
+	r.nbits--
+	return r.word>>r.nbits&1 == 1, nil
+}
+
+// readBits reads k bits, most significant first.
+func (r *graph6Reader) readBits(k uint) (uint64, error) {
+	var x uint64
+	for i := uint(0); i < k; i++ {
+		b, err := r.readBit()
+		if err != nil {
+			return 0, err
+		}
+		x <<= 1
+		if b {
+			x |= 1
+		}
+	}
+	return x, nil
+}
+
+// remaining returns the number of bits left.
+func (r *graph6Reader) remaining() uint {
+	return r.nbits + 6*uint(len(r.src)-r.pos)
+}
+
+// shorter returns whether fewer than a*b bits remain, without computing the product, which could
+// overflow for the sizes of up to 2^36-1 nodes allowed by the format.
+func (r *graph6Reader) shorter(a, b uint64) bool {
+	return a != 0 && uint64(r.remaining())/a < b
+}
+
+// done returns an error if there are bytes left, once all the expected bits have been read.
+func (r *graph6Reader) done() error {
+	if r.pos != len(r.src) {
+		return fmt.Errorf("Unexpected trailing data %q", r.src[r.pos:])
+	}
+	return nil
+}
+
+// graph6Nodes checks the size of the Graph and returns its Index and adjacency lists.
+func graph6Nodes(g *Graph) (*Index, [][]int, error) {
+	x := g.Index()
+	if x.Len() > maxGraph6Nodes {
+		return nil, nil, fmt.Errorf("Graph has too many nodes: %d", x.Len())
+	}
+	return x, g.adjacency(x), nil
+}
+
+// graph6Graph creates a Graph with n nodes, with ids 1..n.
+func graph6Graph(n int, directed bool) *Graph {
+	g := NewGraph(directed)
+	for i := 1; i <= n; i++ {
+		g.AddNode(uint64(i), nil)
+	}
+	return g
+}
+
+// EncodeGraph6 encodes an undirected Graph without self-loops as a graph6 string, well suited for small
+// dense graphs. Nodes are numbered following the Graph Index order. Weights and attributes are not encoded.
+func EncodeGraph6(g *Graph) (string, error) {
+	if g.directed {
+		return "", errors.New("Graph must be undirected")
+	}
+	x, adj, err := graph6Nodes(g)
+	if err != nil {
+		return "", err
+	}
+
+	for i, succ := range adj {
+		for _, j := range succ {
+			if i == j {
+				return "", fmt.Errorf("Self-loops are not supported: node %d", x.ID(i))
+			}
+		}
+	}
+
+	n := x.Len()
+	w := &graph6Writer{}
+	w.writeSize(n)
+	for j := 1; j < n; j++ {
+		// adj[j] is sorted, and holds i if and only if adj[i] holds j
+		succ := adj[j]
+		k := 0
+		for i := 0; i < j; i++ {
+			b := k < len(succ) && succ[k] == i
+			if b {
+				k++
+			}
+			w.writeBit(b)
+		}
+	}
+	return w.String(), nil
+}
+
+// DecodeGraph6 decodes a graph6 string into an undirected Graph. The node at position i is given the id i+1.
+// An optional ">>graph6<<" header is skipped.
+func DecodeGraph6(s string) (*Graph, error) {
+	r := &graph6Reader{src: strings.TrimPrefix(s, graph6Header)}
+	n, err := r.readSize()
+	if err != nil {
+		return nil, err
+	}
+
+	// n(n-1)/2 bits are needed, factored so that one factor is even.
+	a, b := uint64(n), uint64(n-1)/2
+	if n%2 == 0 {
+		a, b = uint64(n)/2, uint64(n-1)
+	}
+	if r.shorter(a, b) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	g := graph6Graph(n, false)
+	for j := 1; j < n; j++ {
+		for i := 0; i < j; i++ {
+			b, err := r.readBit()
+			if err != nil {
+				return nil, err
+			}
+			if b {
+				g.AddEdge(uint64(i+1), uint64(j+1), 1, nil)
+			}
+		}
+	}
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// EncodeDigraph6 encodes a directed Graph as a digraph6 string. Self-loops are supported.
+// Nodes are numbered following the Graph Index order. Weights and attributes are not encoded.
+func EncodeDigraph6(g *Graph) (string, error) {
+	if !g.directed {
+		return "", errors.New("Graph must be directed")
+	}
+	x, adj, err := graph6Nodes(g)
+	if err != nil {
+		return "", err
+	}
+
+	n := x.Len()
+	w := &graph6Writer{buf: []byte{'&'}}
+	w.writeSize(n)
+	for _, succ := range adj {
+		// succ is sorted
+		k := 0
+		for j := 0; j < n; j++ {
+			b := k < len(succ) && succ[k] == j
+			if b {
+				k++
+			}
+			w.writeBit(b)
+		}
+	}
+	return w.String(), nil
+}
+
+// DecodeDigraph6 decodes a digraph6 string into a directed Graph. The node at position i is given the id i+1.
+// An optional ">>digraph6<<" header is skipped.
+func DecodeDigraph6(s string) (*Graph, error) {
+	s = strings.TrimPrefix(s, digraph6Header)
+	if !strings.HasPrefix(s, "&") {
+		return nil, errors.New("Missing digraph6 prefix '&'")
+	}
+	r := &graph6Reader{src: s[1:]}
+	n, err := r.readSize()
+	if err != nil {
+		return nil, err
+	}
+
+	if r.shorter(uint64(n), uint64(n)) {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	g := graph6Graph(n, true)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			b, err := r.readBit()
+			if err != nil {
+				return nil, err
+			}
+			if b {
+				g.AddEdge(uint64(i+1), uint64(j+1), 1, nil)
+			}
+		}
+	}
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// sparse6Bits returns the number of bits needed to represent n-1.
+func sparse6Bits(n int) uint {
+	k := uint(0)
+	for x := n - 1; x > 0; x >>= 1 {
+		k++
+	}
+	return k
+}
+
+// EncodeSparse6 encodes an undirected Graph as a sparse6 string, well suited for large sparse graphs.
+// Self-loops are supported. Nodes are numbered following the Graph Index order.
+// Weights and attributes are not encoded.
+func EncodeSparse6(g *Graph) (string, error) {
+	if g.directed {
+		return "", errors.New("Graph must be undirected")
+	}
+	x, adj, err := graph6Nodes(g)
+	if err != nil {
+		return "", err
+	}
+
+	// edges (u, v) with u <= v, sorted by v and u
+	type pair struct{ u, v int }
+	var edges []pair
+	for u, succ := range adj {
+		for _, v := range succ {
+			if u <= v {
+				edges = append(edges, pair{u, v})
+			}
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].v != edges[j].v {
+			return edges[i].v < edges[j].v
+		}
+		return edges[i].u < edges[j].u
+	})
+
+	n := x.Len()
+	k := sparse6Bits(n)
+	w := &graph6Writer{buf: []byte{':'}}
+	w.writeSize(n)
+
+	cur := 0
+	for _, e := range edges {
+		switch {
+		case e.v == cur:
+			w.writeBit(false)
+		case e.v == cur+1:
+			w.writeBit(true)
+		default:
+			w.writeBit(true)
+			w.writeBits(uint64(e.v), k)
+			w.writeBit(false)
+		}
+		w.writeBits(uint64(e.u), k)
+		cur = e.v
+	}
+
+	// Padding with 1s could be decoded as an extra edge (n-1, n-1) in this case
+	if pad := w.pad(); k < 6 && n == 1<<k && pad >= k+1 && cur == n-2 {
+		w.writeBit(false)
+	}
+	for w.pad() != 0 {
+		w.writeBit(true)
+	}
+	return w.String(), nil
+}
+
+// DecodeSparse6 decodes a sparse6 string into an undirected Graph. The node at position i is given the id i+1.
+// An optional ">>sparse6<<" header is skipped. Incremental sparse6 (';' prefixed) strings are not supported.
+// As isolated nodes take no space in sparse6, strings declaring over 2^20 nodes more than their edges could
+// reference are rejected, instead of allocating them.
+func DecodeSparse6(s string) (*Graph, error) {
+	s = strings.TrimPrefix(s, sparse6Header)
+	if !strings.HasPrefix(s, ":") {
+		return nil, errors.New("Missing sparse6 prefix ':'")
+	}
+	r := &graph6Reader{src: s[1:]}
+	n, err := r.readSize()
+	if err != nil {
+		return nil, err
+	}
+
+	k := sparse6Bits(n)
+	// Every k+1 bits group references at most 2 nodes
+	if referenced := 2 * uint64(r.remaining()/(k+1)); uint64(n) > referenced+maxSparse6Isolated {
+		return nil, fmt.Errorf("Too many nodes for the sparse6 string length: %d", n)
+	}
+
+	g := graph6Graph(n, false)
+	v := uint64(0)
+	for r.remaining() >= k+1 {
+		b, err := r.readBit()
+		if err != nil {
+			return nil, err
+		}
+		x, err := r.readBits(k)
+		if err != nil {
+			return nil, err
+		}
+
+		if b {
+			v++
+		}
+		if x >= uint64(n) || v >= uint64(n) {
+			break
+		} else if x > v {
+			v = x
+		} else {
+			g.AddEdge(x+1, v+1, 1, nil)
+		}
+	}
+	if err := r.done(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Graph6Reader reads graphs from a file in graph6, sparse6 or digraph6 format, one graph per line.
+// The format of every line is detected from its prefix and optional header.
+type Graph6Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewGraph6Reader creates a Graph6Reader reading from r.
+func NewGraph6Reader(r io.Reader) *Graph6Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 256*1024*1024)
+	return &Graph6Reader{scanner: scanner}
+}
+
+// Line returns the line number of the last graph read.
+func (gr *Graph6Reader) Line() int {
+	return gr.line
+}
+
+// Read returns the next graph in the file. At the end of the input, the returned error is io.EOF.
+// Empty lines are skipped, and malformed lines are reported with a *ParseError.
+func (gr *Graph6Reader) Read() (*Graph, error) {
+	for gr.scanner.Scan() {
+		gr.line++
+		line := strings.TrimRight(gr.scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		var g *Graph
+		var err error
+		switch {
+		case strings.HasPrefix(line, sparse6Header), strings.HasPrefix(line, ":"):
+			g, err = DecodeSparse6(line)
+		case strings.HasPrefix(line, digraph6Header), strings.HasPrefix(line, "&"):
+			g, err = DecodeDigraph6(line)
+		case strings.HasPrefix(line, ";"):
+			err = errors.New("Incremental sparse6 is not supported")
+		default:
+			g, err = DecodeGraph6(line)
+		}
+		if err != nil {
+			return nil, &ParseError{gr.line, err}
+		}
+		return g, nil
+	}
+	if err := gr.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,170 @@
+package grapho
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// edges6 returns a Graph with nodes 1..n and the given edges.
+func edges6(n int, directed bool, edges [][2]uint64) *Graph {
+	g := graph6Graph(n, directed)
+	for _, e := range edges {
+		g.AddEdge(e[0], e[1], 1, nil)
+	}
+	return g
+}
+
+func equalGraph6(t *testing.T, name string, g, expected *Graph) {
+	if g.Len() != expected.Len() || g.IsDirected() != expected.IsDirected() {
+		t.Fatalf("%s: %d nodes, directed=%v. Expected %d nodes, directed=%v", name, g.Len(), g.IsDirected(),
+			expected.Len(), expected.IsDirected())
+	}
+	if len(g.sortedEdges()) != len(expected.sortedEdges()) {
+		t.Errorf("%s: %d edges. Expected %d", name, len(g.sortedEdges()), len(expected.sortedEdges()))
+	}
+	for _, e := range expected.sortedEdges() {
+		testEdgeExists(t, g, e.u, e.v, true)
+	}
+}
+
+func TestGraph6(t *testing.T) {
+	// Examples from the format specification
+	tests := []struct {
+		s        string
+		expected *Graph
+	}{
+		{"DQc", edges6(5, false, [][2]uint64{{1, 3}, {1, 5}, {2, 4}, {4, 5}})},
+		{":Fa@x^", edges6(7, false, [][2]uint64{{1, 2}, {1, 3}, {2, 3}, {6, 7}})},
+		{"&DI?AO?", edges6(5, true, [][2]uint64{{1, 3}, {1, 5}, {4, 2}, {4, 5}})},
+	}
+	for _, tt := range tests {
+		var g *Graph
+		var s string
+		var err error
+		switch tt.s[0] {
+		case ':':
+			g, err = DecodeSparse6(tt.s)
+			if err == nil {
+				s, err = EncodeSparse6(tt.expected)
+			}
+		case '&':
+			g, err = DecodeDigraph6(tt.s)
+			if err == nil {
+				s, err = EncodeDigraph6(tt.expected)
+			}
+		default:
+			g, err = DecodeGraph6(tt.s)
+			if err == nil {
+				s, err = EncodeGraph6(tt.expected)
+			}
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.s, err)
+			continue
+		}
+		equalGraph6(t, tt.s, g, tt.expected)
+		if s != tt.s {
+			t.Errorf("Encoded %q. Expected %q", s, tt.s)
+		}
+	}
+
+	// Round trips
+	g := sampleGraph()
+	g.AddEdge(9, 9, 1, nil)
+	s, err := EncodeSparse6(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := DecodeSparse6(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalGraph6(t, s, h, g)
+
+	// Padding special case: n = 2^k, with the last edge at n-2
+	g = edges6(4, false, [][2]uint64{{1, 2}, {2, 3}})
+	s, _ = EncodeSparse6(g)
+	h, err = DecodeSparse6(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalGraph6(t, s, h, g)
+
+	// Sizes not backed by the string length are rejected before allocating the nodes
+	if _, err := DecodeSparse6(":~~~~~~~~~~"); err == nil {
+		t.Error("Expected error decoding a sparse6 string with 2^36-1 nodes")
+	}
+	s, _ = EncodeSparse6(graph6Graph(1000, false))
+	if h, err = DecodeSparse6(s); err != nil || h.Len() != 1000 {
+		t.Errorf("Unexpected decoding for 1000 isolated nodes: %v", err)
+	}
+
+	big := graph6Graph(100, false)
+	big.AddEdge(1, 100, 1, nil)
+	s, err = EncodeGraph6(big)
+	if err != nil || s[0] != 126 {
+		t.Fatalf("Unexpected encoding for 100 nodes: %q, %v", s, err)
+	}
+	h, err = DecodeGraph6(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	equalGraph6(t, "100 nodes", h, big)
+
+	if _, err := EncodeGraph6(sampleDiGraph()); err == nil {
+		t.Error("Expected error encoding a digraph as graph6")
+	}
+	if _, err := EncodeGraph6(edges6(2, false, [][2]uint64{{2, 2}})); err == nil {
+		t.Error("Expected error encoding a self-loop as graph6")
+	}
+	if _, err := EncodeDigraph6(sampleGraph()); err == nil {
+		t.Error("Expected error encoding an undirected graph as digraph6")
+	}
+	for _, s := range []string{"", "D", "DQc?", "D Qc"} {
+		if _, err := DecodeGraph6(s); err == nil {
+			t.Errorf("Expected error decoding %q", s)
+		}
+	}
+
+	// Sizes of 2^32, 2^32+1 and 2^36-1 nodes, whose number of bits overflows uint64 when squared
+	for _, s := range []string{"~~C?????", "~~C????@", "~~~~~~~~"} {
+		if _, err := DecodeGraph6(s); err != io.ErrUnexpectedEOF {
+			t.Errorf("DecodeGraph6(%q): %v. Expected io.ErrUnexpectedEOF", s, err)
+		}
+		if _, err := DecodeDigraph6("&" + s); err != io.ErrUnexpectedEOF {
+			t.Errorf("DecodeDigraph6(%q): %v. Expected io.ErrUnexpectedEOF", "&"+s, err)
+		}
+	}
+}
+
+func TestGraph6Reader(t *testing.T) {
+	src := ">>graph6<<DQc\n\n:Fa@x^\r\n&DI?AO?\nD!\n"
+	gr := NewGraph6Reader(strings.NewReader(src))
+
+	for _, expected := range []struct {
+		nodes    int
+		directed bool
+		line     int
+	}{
+		{5, false, 1}, {7, false, 3}, {5, true, 4},
+	} {
+		g, err := gr.Read()
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		if g.Len() != expected.nodes || g.IsDirected() != expected.directed || gr.Line() != expected.line {
+			t.Errorf("Line %d: %d nodes, directed=%v. Expected line %d: %d nodes, directed=%v", gr.Line(), g.Len(),
+				g.IsDirected(), expected.line, expected.nodes, expected.directed)
+		}
+	}
+
+	if _, err := gr.Read(); err == nil {
+		t.Error("Expected error reading an invalid line")
+	} else if pe, ok := err.(*ParseError); !ok || pe.Line != 5 {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := gr.Read(); err != io.EOF {
+		t.Errorf("Read: %v. Expected io.EOF", err)
+	}
+}
@@ -94,3 +94,64 @@ func TestGraphDeleteEdge(t *testing.T) {
 		t.Errorf("Edge was not successfully deleted")
 	}
 }
+
+func TestGraphAddNodeKeepsEdges(t *testing.T) {
+	g := NewGraph(false)
+	g.AddEdge(1, 2, 1, nil)
+
+	attr := NewAttr()
+	attr["name"] = "Dylan"
+	g.AddNode(1, attr)
+
+	if _, ok := g.Edge(1, 2); !ok {
+		t.Errorf("Edge 1-2 was dropped when updating node 1")
+	}
+	if v := g.Validate(); len(v) != 0 {
+		t.Errorf("Unexpected violations: %v", v)
+	}
+}
+
+func TestGraphAddNodeIfAbsent(t *testing.T) {
+	g := NewGraph(false)
+
+	attr := NewAttr()
+	attr["x"] = 1
+	if !g.AddNodeIfAbsent(1, attr) {
+		t.Errorf("Node 1 should have been added")
+	}
+	if g.AddNodeIfAbsent(1, nil) {
+		t.Errorf("Node 1 should not have been added twice")
+	}
+
+	attr, _ = g.Node(1)
+	if x, ok := attr["x"]; !ok || x.(int) != 1 {
+		t.Errorf("Expected value: 1. Got %v", x)
+	}
+}
+
+func TestGraphUpdateNodeAttr(t *testing.T) {
+	g := NewGraph(false)
+	attr := NewAttr()
+	attr["x"] = 1
+	attr["y"] = 2
+	g.AddNode(1, attr)
+
+	update := NewAttr()
+	update["y"] = 3
+	update["z"] = 4
+	if !g.UpdateNodeAttr(1, update) {
+		t.Fatalf("Node 1 should have been updated")
+	}
+	if !g.SetNodeAttr(1, "name", "Bob") {
+		t.Fatalf("Node 1 should have been updated")
+	}
+
+	attr, _ = g.Node(1)
+	if attr["x"] != 1 || attr["y"] != 3 || attr["z"] != 4 || attr["name"] != "Bob" {
+		t.Errorf("Unexpected attributes: %v", attr)
+	}
+
+	if g.UpdateNodeAttr(2, update) || g.SetNodeAttr(2, "x", 1) {
+		t.Errorf("Non-existent node should not be updated")
+	}
+}
@@ -0,0 +1,287 @@
+// GraphML file format
+// http://graphml.graphdrawing.org/specification.html
+
+package grapho
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// GraphMLWeightKey is the name of the GraphML edge attribute holding Edge.Weight.
+const GraphMLWeightKey = "weight"
+
+const graphmlNamespace = "http://graphml.graphdrawing.org/xmlns"
+
+type graphmlDoc struct {
+	XMLName xml.Name       `xml:"graphml"`
+	Xmlns   string         `xml:"xmlns,attr,omitempty"`
+	Keys    []graphmlKey   `xml:"key"`
+	Graphs  []graphmlGraph `xml:"graph"`
+}
+
+type graphmlKey struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"`
+	Name    string  `xml:"attr.name,attr"`
+	Type    string  `xml:"attr.type,attr"`
+	Default *string `xml:"default"`
+}
+
+type graphmlGraph struct {
+	ID          string        `xml:"id,attr,omitempty"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphmlNode `xml:"node"`
+	Edges       []graphmlEdge `xml:"edge"`
+	Hyperedges  []struct{}    `xml:"hyperedge"`
+}
+
+type graphmlNode struct {
+	ID     string        `xml:"id,attr"`
+	Data   []graphmlData `xml:"data"`
+	Graphs []struct{}    `xml:"graph"`
+	Ports  []struct{}    `xml:"port"`
+}
+
+type graphmlEdge struct {
+	Source     string        `xml:"source,attr"`
+	Target     string        `xml:"target,attr"`
+	Directed   string        `xml:"directed,attr,omitempty"`
+	SourcePort string        `xml:"sourceport,attr,omitempty"`
+	TargetPort string        `xml:"targetport,attr,omitempty"`
+	Data       []graphmlData `xml:"data"`
+	Graphs     []struct{}    `xml:"graph"`
+}
+
+type graphmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the Graph as a GraphML document. Node and edge attributes are declared
+// as GraphML keys, with their type inferred from the values: bool (boolean), int32 and smaller integers
+// (int), int, int64 and unsigned integers (long, up to math.MaxInt64), float32 (float), float64 (double)
+// and string. Other types are not supported. Note that ReadGraphML reads every integer back as int.
+// Edge.Weight is written
+// as the edge attribute GraphMLWeightKey, which therefore cannot be used as an edge Attr key.
+func WriteGraphML(w io.Writer, g *Graph) error {
+	nodes := g.sortedNodeIDs()
+	edges := g.sortedEdges()
+
+	nodeAttrs := make([]Attr, len(nodes))
+	for i, node := range nodes {
+		nodeAttrs[i] = g.nodes[node]
+	}
+	edgeAttrs := make([]Attr, len(edges))
+	for i, edge := range edges {
+		if _, ok := edge.Attr[GraphMLWeightKey]; ok {
+			return fmt.Errorf("Edge attribute %q clashes with the edge weight", GraphMLWeightKey)
+		}
+		edgeAttrs[i] = edge.Attr
+	}
+
+	nodeKinds, err := attrKinds(nodeAttrs)
+	if err != nil {
+		return err
+	}
+	edgeKinds, err := attrKinds(edgeAttrs)
+	if err != nil {
+		return err
+	}
+
+	doc := graphmlDoc{Xmlns: graphmlNamespace}
+
+	// declare keys, binding attribute names to key ids
+	declare := func(domain string, kinds map[string]string) map[string]string {
+		ids := make(map[string]string, len(kinds))
+		for _, name := range sortedKinds(kinds) {
+			id := fmt.Sprintf("d%d", len(doc.Keys))
+			doc.Keys = append(doc.Keys, graphmlKey{id, domain, name, kinds[name], nil})
+			ids[name] = id
+		}
+		return ids
+	}
+	nodeKeys := declare("node", nodeKinds)
+	edgeKeys := declare("edge", edgeKinds)
+	doc.Keys = append(doc.Keys, graphmlKey{GraphMLWeightKey, "edge", GraphMLWeightKey, kindLong, nil})
+
+	data := func(attr Attr, keys map[string]string) []graphmlData {
+		var data []graphmlData
+		for _, k := range sortedKeys(attr) {
+			data = append(data, graphmlData{keys[k], fmt.Sprint(attr[k])})
+		}
+		return data
+	}
+
+	graph := graphmlGraph{ID: "G", EdgeDefault: "undirected"}
+	if g.directed {
+		graph.EdgeDefault = "directed"
+	}
+	for i, node := range nodes {
+		graph.Nodes = append(graph.Nodes, graphmlNode{
+			ID:   fmt.Sprint(node),
+			Data: data(nodeAttrs[i], nodeKeys),
+		})
+	}
+	for _, edge := range edges {
+		graph.Edges = append(graph.Edges, graphmlEdge{
+			Source: fmt.Sprint(edge.u),
+			Target: fmt.Sprint(edge.v),
+			Data:   append(data(edge.Attr, edgeKeys), graphmlData{GraphMLWeightKey, fmt.Sprint(edge.Weight)}),
+		})
+	}
+	doc.Graphs = []graphmlGraph{graph}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+// ReadGraphML builds a Graph from a GraphML document, holding a single graph. Typed node and edge
+// attributes are stored in Attr, with their GraphML declared type (boolean as bool, int and long as int,
+// float as float32, double as float64 and string). Missing values get the key default, if any.
+// The edge attribute named GraphMLWeightKey, if declared as int or long, is read as Edge.Weight
+// instead. Edges without it are given a weight of 1.
+// Hyperedges, ports, nested graphs and graphs mixing directed and undirected edges are not supported.
+// Node ids are bound as in ReadDOT: if every GraphML node id is a decimal uint64, they are used
+// as is. Otherwise, ids are assigned sequentially, starting at 1, in document order.
+// The returned map binds each GraphML node id to its node id.
+func ReadGraphML(r io.Reader) (*Graph, map[string]uint64, error) {
+	var doc graphmlDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, err
+	}
+
+	if len(doc.Graphs) != 1 {
+		return nil, nil, fmt.Errorf("Expected a single graph, found %d", len(doc.Graphs))
+	}
+	graph := doc.Graphs[0]
+	if len(graph.Hyperedges) > 0 {
+		return nil, nil, errors.New("Hyperedges are not supported")
+	}
+
+	directed := graph.EdgeDefault == "directed"
+	if graph.EdgeDefault != "directed" && graph.EdgeDefault != "undirected" {
+		return nil, nil, fmt.Errorf("Invalid edgedefault %q", graph.EdgeDefault)
+	}
+
+	// Keys, by domain and id
+	keys := map[string]map[string]graphmlKey{"node": {}, "edge": {}}
+	for _, key := range doc.Keys {
+		if key.Type == "" {
+			key.Type = kindString
+		}
+		if !validKind(key.Type) {
+			return nil, nil, fmt.Errorf("Key %q: unsupported type %q", key.ID, key.Type)
+		}
+		if key.Name == "" {
+			key.Name = key.ID
+		}
+		switch key.For {
+		case "node", "edge":
+			keys[key.For][key.ID] = key
+		case "all":
+			keys["node"][key.ID] = key
+			keys["edge"][key.ID] = key
+		}
+	}
+
+	// attributes builds the Attr of a node/edge, from its data entries and the key defaults
+	attributes := func(domain string, data []graphmlData) (Attr, error) {
+		attr := NewAttr()
+		for _, key := range keys[domain] {
+			if key.Default != nil {
+				v, err := parseKind(key.Type, *key.Default)
+				if err != nil {
+					return nil, fmt.Errorf("Key %q: invalid default %q", key.ID, *key.Default)
+				}
+				attr[key.Name] = v
+			}
+		}
+		for _, d := range data {
+			key, ok := keys[domain][d.Key]
+			if !ok {
+				return nil, fmt.Errorf("Undeclared %s key %q", domain, d.Key)
+			}
+			v, err := parseKind(key.Type, d.Value)
+			if err != nil {
+				return nil, fmt.Errorf("Key %q: invalid value %q", key.ID, d.Value)
+			}
+			attr[key.Name] = v
+		}
+		return attr, nil
+	}
+
+	names := make([]string, 0, len(graph.Nodes))
+	for _, node := range graph.Nodes {
+		if len(node.Graphs) > 0 {
+			return nil, nil, fmt.Errorf("Node %q: nested graphs are not supported", node.ID)
+		}
+		if len(node.Ports) > 0 {
+			return nil, nil, fmt.Errorf("Node %q: ports are not supported", node.ID)
+		}
+		names = append(names, node.ID)
+	}
+	for _, edge := range graph.Edges {
+		names = append(names, edge.Source, edge.Target)
+	}
+	ids := assignIDs(names)
+
+	g := NewGraph(directed)
+	for _, node := range graph.Nodes {
+		attr, err := attributes("node", node.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Node %q: %v", node.ID, err)
+		}
+		g.AddNode(ids[node.ID], attr)
+	}
+
+	for _, edge := range graph.Edges {
+		name := fmt.Sprintf("Edge %q-%q", edge.Source, edge.Target)
+		if len(edge.Graphs) > 0 {
+			return nil, nil, fmt.Errorf("%s: nested graphs are not supported", name)
+		}
+		if edge.SourcePort != "" || edge.TargetPort != "" {
+			return nil, nil, fmt.Errorf("%s: ports are not supported", name)
+		}
+		if edge.Directed != "" && (edge.Directed == "true") != directed {
+			return nil, nil, fmt.Errorf("%s: mixed directed and undirected edges are not supported", name)
+		}
+
+		attr, err := attributes("edge", edge.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", name, err)
+		}
+
+		weight := 1
+		switch v := attr[GraphMLWeightKey].(type) {
+		case int:
+			weight = v
+			delete(attr, GraphMLWeightKey)
+		}
+
+		g.AddEdge(ids[edge.Source], ids[edge.Target], weight, attr)
+	}
+
+	return g, ids, nil
+}
+
+// sortedKinds returns the attribute names of the given type map, in increasing order.
+func sortedKinds(kinds map[string]string) []string {
+	names := make([]string, 0, len(kinds))
+	for k := range kinds {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
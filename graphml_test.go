@@ -0,0 +1,131 @@
+package grapho
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGraphMLRoundTrip(t *testing.T) {
+	g := NewGraph(true)
+	g.AddNode(1, Attr{"name": "Bob", "age": 30, "score": 1.5, "admin": true})
+	g.AddNode(2, Attr{"name": "Alice", "age": int64(40)})
+	g.AddEdge(1, 2, 7, Attr{"since": float32(2.5)})
+	g.AddEdge(2, 3, 1, nil)
+
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, g); err != nil {
+		t.Fatalf("WriteGraphML: %v", err)
+	}
+
+	read, ids, err := ReadGraphML(&buf)
+	if err != nil {
+		t.Fatalf("ReadGraphML: %v", err)
+	}
+	if !read.IsDirected() || read.Len() != 3 || ids["3"] != 3 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", read.IsDirected(), read.Len())
+	}
+
+	attr, _ := read.Node(1)
+	if attr["name"] != "Bob" || attr["age"] != 30 || attr["score"] != 1.5 || attr["admin"] != true {
+		t.Errorf("Unexpected attributes for node 1: %v", attr)
+	}
+	attr, _ = read.Node(2)
+	if attr["age"] != 40 {
+		t.Errorf("Unexpected attributes for node 2: %v", attr)
+	}
+
+	edge, ok := read.Edge(1, 2)
+	if !ok || edge.Weight != 7 || edge.Attr["since"] != float32(2.5) {
+		t.Errorf("Unexpected edge 1-2: %v", edge)
+	}
+	testEdgeExists(t, read, 2, 1, false)
+	testEdgeExists(t, read, 2, 3, true)
+}
+
+func TestReadGraphML(t *testing.T) {
+	src := `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="color" attr.type="string"><default>yellow</default></key>
+  <key id="d1" for="edge" attr.name="weight" attr.type="long"/>
+  <graph id="G" edgedefault="undirected">
+    <node id="n0"><data key="d0">green</data></node>
+    <node id="n1"/>
+    <edge source="n0" target="n1"><data key="d1">3</data></edge>
+    <edge source="n1" target="n2"/>
+  </graph>
+</graphml>`
+	g, ids, err := ReadGraphML(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadGraphML: %v", err)
+	}
+	if g.IsDirected() || g.Len() != 3 || ids["n0"] != 1 || ids["n2"] != 3 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes, ids %v", g.IsDirected(), g.Len(), ids)
+	}
+	if attr, _ := g.Node(1); attr["color"] != "green" {
+		t.Errorf("Unexpected attributes for n0: %v", attr)
+	}
+	if attr, _ := g.Node(2); attr["color"] != "yellow" {
+		t.Errorf("Unexpected attributes for n1: %v", attr)
+	}
+	if edge, _ := g.Edge(2, 1); edge.Weight != 3 {
+		t.Errorf("Edge n0-n1 weight: %d. Expected 3", edge.Weight)
+	}
+	if edge, _ := g.Edge(2, 3); edge.Weight != 1 {
+		t.Errorf("Edge n1-n2 weight: %d. Expected 1", edge.Weight)
+	}
+}
+
+func TestReadGraphMLUnsupported(t *testing.T) {
+	for _, body := range []string{
+		`<graph edgedefault="directed"><hyperedge><endpoint node="a"/></hyperedge></graph>`,
+		`<graph edgedefault="directed"><node id="a"><graph edgedefault="directed"/></node></graph>`,
+		`<graph edgedefault="directed"><edge source="a" target="b" directed="false"/></graph>`,
+		`<graph edgedefault="directed"/><graph edgedefault="directed"/>`,
+		`<graph edgedefault="directed"><node id="a"><data key="x">1</data></node></graph>`,
+	} {
+		src := `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + body + `</graphml>`
+		if _, _, err := ReadGraphML(strings.NewReader(src)); err == nil {
+			t.Errorf("ReadGraphML(%q): Did not get expected error", body)
+		}
+	}
+
+	g := NewGraph(false)
+	g.AddEdge(1, 2, 1, Attr{"weight": 3})
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, g); err == nil {
+		t.Errorf("WriteGraphML: Did not get expected error")
+	}
+}
+
+// TestGraphMLIntegers tests that Go int and unsigned values are declared as long, and read back
+func TestGraphMLIntegers(t *testing.T) {
+	g := NewGraph(false)
+	g.AddNode(1, Attr{"big": 1 << 40, "small": int32(7), "unsigned": uint64(math.MaxInt64)})
+
+	var buf bytes.Buffer
+	if err := WriteGraphML(&buf, g); err != nil {
+		t.Fatalf("WriteGraphML: %v", err)
+	}
+	for _, key := range []string{`attr.name="big" attr.type="long"`, `attr.name="small" attr.type="int"`, `attr.name="unsigned" attr.type="long"`} {
+		if !strings.Contains(buf.String(), key) {
+			t.Errorf("WriteGraphML: missing key %s in %s", key, buf.String())
+		}
+	}
+
+	read, _, err := ReadGraphML(&buf)
+	if err != nil {
+		t.Fatalf("ReadGraphML: %v", err)
+	}
+	attr, _ := read.Node(1)
+	if attr["big"] != 1<<40 || attr["small"] != 7 || attr["unsigned"] != math.MaxInt64 {
+		t.Errorf("Unexpected attributes: %v", attr)
+	}
+
+	// Unsigned values above MaxInt64 cannot be read back as long
+	g.AddNode(2, Attr{"unsigned": uint64(math.MaxUint64)})
+	if err := WriteGraphML(&buf, g); err == nil {
+		t.Errorf("WriteGraphML: Did not get expected error")
+	}
+}
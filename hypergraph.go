@@ -0,0 +1,245 @@
+package grapho
+
+import "sort"
+
+// HyperedgeKey is the attribute key used to flag the nodes representing
+// hyperedges in the incidence Graph. Its value is the hyperedge id.
+const HyperedgeKey = "hyperedge"
+
+// Hyperedge represents a relationship between an arbitrary set of nodes.
+type Hyperedge struct {
+	Nodes  []uint64 // Nodes connected by the hyperedge, ordered by ascending node uint64 value
+	Weight int      // Hyperedge weight (cost)
+	Attr   Attr     // Hyperedge attribute set
+}
+
+// Hypergraph implementation. Hyperedges are undirected, identified by
+// a uint64 id, and can connect any number of nodes.
+type Hypergraph struct {
+	nodes     map[uint64]Attr                // Nodes present in the Hypergraph, with their attributes
+	edges     map[uint64]*Hyperedge          // Hyperedges, by id
+	incidence map[uint64]map[uint64]struct{} // Hyperedge ids each node belongs to
+}
+
+// NewHypergraph creates an empty Hypergraph.
+func NewHypergraph() *Hypergraph {
+	return &Hypergraph{
+		nodes:     make(map[uint64]Attr),
+		edges:     make(map[uint64]*Hyperedge),
+		incidence: make(map[uint64]map[uint64]struct{}),
+	}
+}
+
+// Len returns the number of nodes in the Hypergraph
+func (h *Hypergraph) Len() int {
+	return len(h.nodes)
+}
+
+// copyAttr returns a shallow copy of the given attributes. A nil Attr yields an empty one.
+func copyAttr(attr Attr) Attr {
+	c := make(Attr, len(attr))
+	for k, v := range attr {
+		c[k] = v
+	}
+	return c
+}
+
+// AddNode adds the given node to the Hypergraph, with a copy of the given attributes.
+// If the node already exists, it will override its attributes, keeping its hyperedges.
+func (h *Hypergraph) AddNode(node uint64, attr Attr) {
+	h.nodes[node] = copyAttr(attr)
+	if _, ok := h.incidence[node]; !ok {
+		h.incidence[node] = make(map[uint64]struct{})
+	}
+}
+
+// DeleteNode removes a node entry from the Hypergraph. The node is removed
+// from every hyperedge containing it. Hyperedges left empty are removed too.
+func (h *Hypergraph) DeleteNode(node uint64) {
+	for id := range h.incidence[node] {
+		edge := h.edges[id]
+		nodes := edge.Nodes[:0]
+		for _, n := range edge.Nodes {
+			if n != node {
+				nodes = append(nodes, n)
+			}
+		}
+		edge.Nodes = nodes
+
+		if len(edge.Nodes) == 0 {
+			delete(h.edges, id)
+		}
+	}
+
+	delete(h.incidence, node)
+	delete(h.nodes, node)
+}
+
+// Nodes returns the list of nodes in the Hypergraph (unsorted).
+func (h *Hypergraph) Nodes() []uint64 {
+	nodes := make([]uint64, 0, len(h.nodes))
+	for k := range h.nodes {
+		nodes = append(nodes, k)
+	}
+	return nodes
+}
+
+// Node returns the attributes associated with a given node, and
+// a bool flag set to true if the node was found, false otherwise.
+func (h *Hypergraph) Node(node uint64) (Attr, bool) {
+	attr, ok := h.nodes[node]
+	return attr, ok
+}
+
+// AddHyperedge adds a hyperedge (with a copy of its attributes) connecting the given nodes.
+// If the nodes don't exist, they will be automatically created. Duplicated nodes are ignored.
+// If a hyperedge with the same id already existed, it will be replaced.
+func (h *Hypergraph) AddHyperedge(id uint64, nodes []uint64, weight int, attr Attr) {
+	h.DeleteHyperedge(id)
+
+	set := make([]uint64, 0, len(nodes))
+	for _, node := range nodes {
+		if _, ok := h.nodes[node]; !ok {
+			h.AddNode(node, nil)
+		}
+		if _, ok := h.incidence[node][id]; !ok {
+			h.incidence[node][id] = struct{}{}
+			set = append(set, node)
+		}
+	}
+	sort.Sort(uint64Slice(set))
+
+	h.edges[id] = &Hyperedge{set, weight, copyAttr(attr)}
+}
+
+// DeleteHyperedge removes the hyperedge with the given id, if exists.
+func (h *Hypergraph) DeleteHyperedge(id uint64) {
+	if edge, ok := h.edges[id]; ok {
+		for _, node := range edge.Nodes {
+			delete(h.incidence[node], id)
+		}
+		delete(h.edges, id)
+	}
+}
+
+// Hyperedges returns the list of hyperedge ids in the Hypergraph (unsorted).
+func (h *Hypergraph) Hyperedges() []uint64 {
+	ids := make([]uint64, 0, len(h.edges))
+	for k := range h.edges {
+		ids = append(ids, k)
+	}
+	return ids
+}
+
+// Hyperedge returns a copy of the Hyperedge associated with the given id. Changes to it
+// do not affect the Hypergraph. An extra bool flag determines whether the hyperedge was found.
+func (h *Hypergraph) Hyperedge(id uint64) (*Hyperedge, bool) {
+	edge, ok := h.edges[id]
+	if !ok {
+		return nil, false
+	}
+
+	nodes := make([]uint64, len(edge.Nodes))
+	copy(nodes, edge.Nodes)
+	return &Hyperedge{nodes, edge.Weight, copyAttr(edge.Attr)}, true
+}
+
+// Incident returns the ids of the hyperedges containing the given node,
+// ordered by ascending uint64 value. An extra bool flag determines whether the node was found.
+func (h *Hypergraph) Incident(node uint64) ([]uint64, bool) {
+	edges, ok := h.incidence[node]
+	if !ok {
+		return nil, false
+	}
+
+	ids := make([]uint64, 0, len(edges))
+	for k := range edges {
+		ids = append(ids, k)
+	}
+	sort.Sort(uint64Slice(ids))
+
+	return ids, true
+}
+
+// Members returns the nodes connected by the given hyperedge, ordered by
+// ascending node uint64 value. An extra bool flag determines whether the hyperedge was found.
+func (h *Hypergraph) Members(id uint64) ([]uint64, bool) {
+	edge, ok := h.edges[id]
+	if !ok {
+		return nil, false
+	}
+
+	nodes := make([]uint64, len(edge.Nodes))
+	copy(nodes, edge.Nodes)
+	return nodes, true
+}
+
+// IncidenceGraph converts the Hypergraph into its bipartite incidence Graph (undirected):
+// every hyperedge becomes a node, linked to each of its members with an edge holding
+// the hyperedge weight. As both nodes and hyperedges share the same id space in the
+// resulting Graph, hyperedge nodes are given new ids, after the largest node id. If they
+// overflow, they wrap around to 0, skipping the ids already taken.
+// Their attributes are a copy of the hyperedge ones, with HyperedgeKey set to the
+// hyperedge id. Member nodes get a copy of their attributes too.
+// The returned map binds each hyperedge id to its node in the Graph.
+func (h *Hypergraph) IncidenceGraph() (*Graph, map[uint64]uint64) {
+	g := NewGraph(false)
+
+	var largest uint64
+	for node, attr := range h.nodes {
+		g.AddNode(node, copyAttr(attr))
+		if node > largest {
+			largest = node
+		}
+	}
+	next := largest + 1 // wraps around to 0 for math.MaxUint64
+
+	ids := h.Hyperedges()
+	sort.Sort(uint64Slice(ids))
+
+	mapping := make(map[uint64]uint64, len(ids))
+	for _, id := range ids {
+		edge := h.edges[id]
+
+		attr := copyAttr(edge.Attr)
+		attr[HyperedgeKey] = id
+
+		for _, ok := g.Node(next); ok; _, ok = g.Node(next) {
+			next++
+		}
+		g.AddNode(next, attr)
+		for _, node := range edge.Nodes {
+			g.AddEdge(node, next, edge.Weight, nil)
+		}
+
+		mapping[id] = next
+		next++
+	}
+
+	return g, mapping
+}
+
+// CliqueExpansion converts the Hypergraph into an undirected Graph, where the members
+// of every hyperedge are pairwise connected. Since the Graph does not allow parallel edges,
+// the weight of an edge is the sum of the weights of all the hyperedges containing both nodes.
+// Nodes get a copy of their attributes.
+func (h *Hypergraph) CliqueExpansion() *Graph {
+	g := NewGraph(false)
+	for node, attr := range h.nodes {
+		g.AddNode(node, copyAttr(attr))
+	}
+
+	for _, edge := range h.edges {
+		for i, u := range edge.Nodes {
+			for _, v := range edge.Nodes[i+1:] {
+				if e, ok := g.Edge(u, v); ok {
+					e.Weight += edge.Weight
+				} else {
+					g.AddEdge(u, v, edge.Weight, nil)
+				}
+			}
+		}
+	}
+
+	return g
+}
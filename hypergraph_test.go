@@ -0,0 +1,161 @@
+package grapho
+
+import (
+	"math"
+	"testing"
+)
+
+// sampleHypergraph creates a simple Hypergraph for testing purposes
+func sampleHypergraph() *Hypergraph {
+	h := NewHypergraph()
+	h.AddHyperedge(1, []uint64{1, 2, 3}, 1, nil)
+	h.AddHyperedge(2, []uint64{3, 4}, 2, nil)
+	h.AddHyperedge(3, []uint64{2, 3, 5, 3}, 3, nil)
+	return h
+}
+
+func TestHypergraphIncidence(t *testing.T) {
+	h := sampleHypergraph()
+
+	if h.Len() != 5 {
+		t.Errorf("Expected size %d, got %d", 5, h.Len())
+	}
+
+	edges, ok := h.Incident(3)
+	if !ok || !EqualsIntSlice(edges, []uint64{1, 2, 3}) {
+		t.Errorf("Incident(3): %v. Expected: %v", edges, []uint64{1, 2, 3})
+	}
+
+	nodes, ok := h.Members(3)
+	if !ok || !EqualsIntSlice(nodes, []uint64{2, 3, 5}) {
+		t.Errorf("Members(3): %v. Expected: %v", nodes, []uint64{2, 3, 5})
+	}
+
+	h.DeleteNode(4)
+	if _, ok := h.Hyperedge(2); !ok {
+		t.Errorf("Hyperedge 2 should still be present")
+	}
+	h.DeleteNode(3)
+	if _, ok := h.Hyperedge(2); ok {
+		t.Errorf("Empty hyperedge 2 should have been removed")
+	}
+	nodes, _ = h.Members(1)
+	if !EqualsIntSlice(nodes, []uint64{1, 2}) {
+		t.Errorf("Members(1): %v. Expected: %v", nodes, []uint64{1, 2})
+	}
+
+	h.DeleteHyperedge(1)
+	if edges, _ := h.Incident(1); len(edges) != 0 {
+		t.Errorf("Incident(1): %v. Expected no hyperedges", edges)
+	}
+}
+
+func TestHypergraphIncidenceGraph(t *testing.T) {
+	g, mapping := sampleHypergraph().IncidenceGraph()
+
+	if g.Len() != 8 {
+		t.Errorf("Expected size %d, got %d", 8, g.Len())
+	}
+
+	node := mapping[3]
+	attr, ok := g.Node(node)
+	if !ok || attr[HyperedgeKey] != uint64(3) {
+		t.Fatalf("Hyperedge 3 not found in the incidence graph")
+	}
+	nodes, _ := g.Neighbors(node)
+	if !EqualsIntSlice(nodes, []uint64{2, 3, 5}) {
+		t.Errorf("Neighbors: %v. Expected: %v", nodes, []uint64{2, 3, 5})
+	}
+
+	// Path from 1 to 4 goes through hyperedges 1 and 2
+	path, err := Search(g, 1, 4, BreadthFirstSearch, nil)
+	if err != nil {
+		t.Fatalf("BreadthFirstSearch: %v", err)
+	}
+	expected := []uint64{1, mapping[1], 3, mapping[2], 4}
+	if !equalPath(path, expected) {
+		t.Errorf("Path: %v. Expected: %v", path, expected)
+	}
+}
+
+// TestHypergraphIncidenceGraphOverflow tests that hyperedge node ids wrap around without reusing member ids
+func TestHypergraphIncidenceGraphOverflow(t *testing.T) {
+	h := NewHypergraph()
+	h.AddHyperedge(1, []uint64{0, math.MaxUint64}, 1, nil)
+	h.AddHyperedge(2, []uint64{2, math.MaxUint64}, 1, nil)
+
+	g, mapping := h.IncidenceGraph()
+	if g.Len() != 5 {
+		t.Fatalf("Expected size %d, got %d", 5, g.Len())
+	}
+	if mapping[1] != 1 || mapping[2] != 3 {
+		t.Errorf("Unexpected hyperedge nodes: %v", mapping)
+	}
+	for id, node := range mapping {
+		if attr, _ := g.Node(node); attr[HyperedgeKey] != id {
+			t.Errorf("Node %d: %v. Expected hyperedge %d", node, attr, id)
+		}
+	}
+}
+
+func TestHypergraphCliqueExpansion(t *testing.T) {
+	g := sampleHypergraph().CliqueExpansion()
+
+	testEdgeExists(t, g, 1, 2, true)
+	testEdgeExists(t, g, 1, 3, true)
+	testEdgeExists(t, g, 3, 4, true)
+	testEdgeExists(t, g, 2, 5, true)
+	testEdgeExists(t, g, 1, 4, false)
+	testEdgeExists(t, g, 4, 5, false)
+
+	// 2-3 belongs to hyperedges 1 and 3
+	if edge, _ := g.Edge(2, 3); edge.Weight != 4 {
+		t.Errorf("Edge 2-3 weight: %d. Expected 4", edge.Weight)
+	}
+	if v := g.Validate(); len(v) != 0 {
+		t.Errorf("Unexpected violations: %v", v)
+	}
+}
+
+// TestHypergraphAttrCopy tests that attributes and hyperedges are not shared with callers or derived graphs
+func TestHypergraphAttrCopy(t *testing.T) {
+	h := NewHypergraph()
+	nodeAttr := Attr{"name": "a"}
+	edgeAttr := Attr{"name": "e"}
+	h.AddNode(1, nodeAttr)
+	h.AddHyperedge(1, []uint64{1, 2}, 1, edgeAttr)
+
+	nodeAttr["name"] = "b"
+	edgeAttr["name"] = "f"
+	if attr, _ := h.Node(1); attr["name"] != "a" {
+		t.Errorf("Node attr changed by the caller: %v", attr)
+	}
+
+	edge, _ := h.Hyperedge(1)
+	if edge.Attr["name"] != "e" {
+		t.Errorf("Hyperedge attr changed by the caller: %v", edge.Attr)
+	}
+	edge.Nodes[0] = 3
+	edge.Attr["name"] = "f"
+	if edge, _ := h.Hyperedge(1); !EqualsIntSlice(edge.Nodes, []uint64{1, 2}) || edge.Attr["name"] != "e" {
+		t.Errorf("Hyperedge changed through a returned copy: %v", edge)
+	}
+
+	g, mapping := h.IncidenceGraph()
+	attr, _ := g.Node(1)
+	attr["name"] = "c"
+	attr, _ = g.Node(mapping[1])
+	attr["name"] = "g"
+	if attr, _ := h.Node(1); attr["name"] != "a" {
+		t.Errorf("Node attr changed through the incidence graph: %v", attr)
+	}
+	if edge, _ := h.Hyperedge(1); edge.Attr["name"] != "e" {
+		t.Errorf("Hyperedge attr changed through the incidence graph: %v", edge.Attr)
+	}
+
+	attr, _ = h.CliqueExpansion().Node(1)
+	attr["name"] = "d"
+	if attr, _ := h.Node(1); attr["name"] != "a" {
+		t.Errorf("Node attr changed through the clique expansion: %v", attr)
+	}
+}
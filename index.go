@@ -0,0 +1,75 @@
+package grapho
+
+import "sort"
+
+// Index maps a set of node ids to the dense range 0..n-1 and back, so that
+// per-node data can be held in slices (or bitsets) instead of maps.
+type Index struct {
+	ids []uint64       // Node id at each position
+	pos map[uint64]int // Position of each node id
+}
+
+// NewIndex creates an Index for the given node ids, preserving their order.
+// Duplicated ids are ignored.
+func NewIndex(ids []uint64) *Index {
+	x := &Index{
+		ids: make([]uint64, 0, len(ids)),
+		pos: make(map[uint64]int, len(ids)),
+	}
+	for _, id := range ids {
+		if _, ok := x.pos[id]; !ok {
+			x.pos[id] = len(x.ids)
+			x.ids = append(x.ids, id)
+		}
+	}
+	return x
+}
+
+// Index creates an Index for the nodes in the Graph, ordered by ascending node uint64 value.
+// The Index is a snapshot: adding or removing nodes afterwards will not update it.
+func (g *Graph) Index() *Index {
+	ids := g.Nodes()
+	sort.Sort(uint64Slice(ids))
+	return NewIndex(ids)
+}
+
+// Len returns the number of nodes in the Index
+func (x *Index) Len() int {
+	return len(x.ids)
+}
+
+// ID returns the node id at position i. It panics if i is out of range.
+func (x *Index) ID(i int) uint64 {
+	return x.ids[i]
+}
+
+// Pos returns the position of the given node id, and a bool
+// flag set to true if the node was found, false otherwise.
+func (x *Index) Pos(id uint64) (int, bool) {
+	i, ok := x.pos[id]
+	return i, ok
+}
+
+// IDs returns the node ids, ordered by position.
+func (x *Index) IDs() []uint64 {
+	ids := make([]uint64, len(x.ids))
+	copy(ids, x.ids)
+	return ids
+}
+
+// adjacency returns the successors of every node in the Graph, as positions of the given Index.
+// Successors are sorted by position. Edges to nodes not present in the Index are skipped.
+func (g *Graph) adjacency(x *Index) [][]int {
+	adj := make([][]int, x.Len())
+	for i, u := range x.ids {
+		succ := make([]int, 0, len(g.edges[u]))
+		for v := range g.edges[u] {
+			if j, ok := x.pos[v]; ok {
+				succ = append(succ, j)
+			}
+		}
+		sort.Ints(succ)
+		adj[i] = succ
+	}
+	return adj
+}
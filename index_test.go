@@ -0,0 +1,84 @@
+package grapho
+
+import (
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	g := NewGraph(true)
+	g.AddEdge(30, 10, 1, nil)
+	g.AddEdge(10, 20, 1, nil)
+
+	x := g.Index()
+	if x.Len() != 3 || !EqualsIntSlice(x.IDs(), []uint64{10, 20, 30}) {
+		t.Fatalf("Unexpected index: %v", x.IDs())
+	}
+	for i := 0; i < x.Len(); i++ {
+		if pos, ok := x.Pos(x.ID(i)); !ok || pos != i {
+			t.Errorf("Pos(%d): %d. Expected %d", x.ID(i), pos, i)
+		}
+	}
+	if _, ok := x.Pos(40); ok {
+		t.Errorf("Node 40 should not be indexed")
+	}
+
+	adj := g.adjacency(x)
+	if len(adj[0]) != 1 || adj[0][0] != 1 || len(adj[1]) != 0 || adj[2][0] != 0 {
+		t.Errorf("Unexpected adjacency: %v", adj)
+	}
+
+	x = NewIndex([]uint64{5, 3, 5, 1})
+	if !EqualsIntSlice(x.IDs(), []uint64{5, 3, 1}) {
+		t.Errorf("Unexpected index: %v", x.IDs())
+	}
+}
+
+// largeGraph creates a sparse, connected Graph with n nodes and sparse ids
+func largeGraph(n int) *Graph {
+	g := NewGraph(false)
+	for i := 1; i < n; i++ {
+		g.AddEdge(uint64(i*7919), uint64((i+1)*7919), i%13, nil)
+		g.AddEdge(uint64(i*7919), uint64((i*31%n+1)*7919), i%7, nil)
+	}
+	return g
+}
+
+func BenchmarkIsConnected(b *testing.B) {
+	g := largeGraph(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		IsConnected(g)
+	}
+}
+
+func BenchmarkPrimMst(b *testing.B) {
+	g := largeGraph(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		PrimMst(g)
+	}
+}
+
+func BenchmarkDijkstra(b *testing.B) {
+	g := largeGraph(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Search(g, 7919, 100000*7919, Dijkstra, nil)
+	}
+}
+
+func BenchmarkBreadthFirstSearch(b *testing.B) {
+	g := largeGraph(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Search(g, 7919, 100000*7919, BreadthFirstSearch, nil)
+	}
+}
+
+func BenchmarkShortestPathTree(b *testing.B) {
+	g := largeGraph(100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ShortestPathTree(g, 7919, Dijkstra)
+	}
+}
@@ -0,0 +1,250 @@
+package grapho
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// edgeRef references an edge of a Graph, along with its endpoints.
+type edgeRef struct {
+	u, v uint64
+	*Edge
+}
+
+// sortedEdges returns the list of edges in the Graph, ordered by source and target node.
+// In undirected graphs, every edge is returned once, with u <= v.
+func (g *Graph) sortedEdges() []edgeRef {
+	var edges []edgeRef
+	for _, u := range g.sortedNodeIDs() {
+		succ := make([]uint64, 0, len(g.edges[u]))
+		for v := range g.edges[u] {
+			if g.directed || u <= v {
+				succ = append(succ, v)
+			}
+		}
+		sort.Sort(uint64Slice(succ))
+
+		for _, v := range succ {
+			edges = append(edges, edgeRef{u, v, g.edges[u][v]})
+		}
+	}
+	return edges
+}
+
+// sortedKeys returns the keys of the given attribute set, in increasing order.
+func sortedKeys(attr Attr) []string {
+	keys := make([]string, 0, len(attr))
+	for k := range attr {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ParseError is returned when the input of a reader is malformed,
+// reporting the line where the error was found.
+type ParseError struct {
+	Line int   // Line number, starting at 1
+	Err  error // The actual error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// assignIDs binds the node names used by an external format to Graph node ids.
+// If every name is a decimal uint64, names are used as ids. Otherwise, ids are
+// assigned sequentially, starting at 1, in the order of the names in the list.
+func assignIDs(names []string) map[string]uint64 {
+	ids := make(map[string]uint64, len(names))
+
+	numeric := true
+	for _, name := range names {
+		id, err := strconv.ParseUint(name, 10, 64)
+		if err != nil || strconv.FormatUint(id, 10) != name {
+			numeric = false
+			break
+		}
+		ids[name] = id
+	}
+	if numeric {
+		return ids
+	}
+
+	ids = make(map[string]uint64, len(names))
+	next := uint64(1)
+	for _, name := range names {
+		if _, ok := ids[name]; !ok {
+			ids[name] = next
+			next++
+		}
+	}
+	return ids
+}
+
+// maxIsolatedNodes is the largest number of nodes an input may declare beyond those referenced by its
+// edges. As isolated nodes take no space, their number is not bounded by the input length.
+const maxIsolatedNodes = 1 << 20
+
+// addDeclaredNodes adds the nodes 1..n missing from the Graph, once the edges of an input declaring
+// n nodes have been read, rejecting inputs which declare too many isolated nodes to be trusted.
+func addDeclaredNodes(g *Graph, n int) error {
+	if n-g.Len() > maxIsolatedNodes {
+		return fmt.Errorf("Too many isolated nodes: %d declared, %d referenced", n, g.Len())
+	}
+	for i := 1; i <= n; i++ {
+		g.AddNodeIfAbsent(uint64(i), nil)
+	}
+	return nil
+}
+
+// Attribute value types shared by the typed formats (GraphML, GEXF...).
+const (
+	kindBoolean = "boolean"
+	kindInt     = "int"
+	kindLong    = "long"
+	kindFloat   = "float"
+	kindDouble  = "double"
+	kindString  = "string"
+)
+
+// attrKind returns the type of an attribute value, or an empty string if it is not supported.
+// As the int type is 32 bits wide in the typed formats, Go int values are declared as long.
+func attrKind(v interface{}) string {
+	switch v.(type) {
+	case bool:
+		return kindBoolean
+	case int8, int16, int32, uint8, uint16:
+		return kindInt
+	case int, int64, uint32, uint, uint64:
+		return kindLong
+	case float32:
+		return kindFloat
+	case float64:
+		return kindDouble
+	case string:
+		return kindString
+	}
+	return ""
+}
+
+// checkAttrRange returns an error if an unsigned attribute value does not fit into a long,
+// as it could be written, but not read back.
+func checkAttrRange(k string, v interface{}) error {
+	var u uint64
+	switch v := v.(type) {
+	case uint:
+		u = uint64(v)
+	case uint64:
+		u = v
+	default:
+		return nil
+	}
+	if u > math.MaxInt64 {
+		return fmt.Errorf("Value %d of attribute %q out of range", u, k)
+	}
+	return nil
+}
+
+// widenKind returns the type able to hold the values of both types,
+// and false if there is none (i.e. mixing numbers and strings).
+func widenKind(a, b string) (string, bool) {
+	switch {
+	case a == "":
+		return b, true
+	case b == "" || a == b:
+		return a, true
+	}
+	rank := map[string]int{kindInt: 1, kindLong: 2, kindFloat: 3, kindDouble: 4}
+	ra, rb := rank[a], rank[b]
+	switch {
+	case ra == 0 || rb == 0:
+		return "", false
+	case ra <= 2 && rb <= 2:
+		return kindLong, true
+	}
+	return kindDouble, true
+}
+
+// attrKinds infers the type of every key present in the given attribute sets.
+func attrKinds(attrs []Attr) (map[string]string, error) {
+	kinds := make(map[string]string)
+	for _, attr := range attrs {
+		for k, v := range attr {
+			kind := attrKind(v)
+			if kind == "" {
+				return nil, fmt.Errorf("Unsupported type %T for attribute %q", v, k)
+			}
+			if err := checkAttrRange(k, v); err != nil {
+				return nil, err
+			}
+			widened, ok := widenKind(kinds[k], kind)
+			if !ok {
+				return nil, fmt.Errorf("Mixed types %s and %s for attribute %q", kinds[k], kind, k)
+			}
+			kinds[k] = widened
+		}
+	}
+	return kinds, nil
+}
+
+// validKind returns whether kind is one of the supported attribute value types.
+func validKind(kind string) bool {
+	switch kind {
+	case kindBoolean, kindInt, kindLong, kindFloat, kindDouble, kindString:
+		return true
+	}
+	return false
+}
+
+// parseKind parses the textual representation of a value of the given type.
+// Both int and long values are parsed as Go int, which is 64 bits wide as Edge.Weight.
+func parseKind(kind, s string) (interface{}, error) {
+	switch kind {
+	case kindBoolean:
+		return strconv.ParseBool(strings.TrimSpace(s))
+	case kindInt, kindLong:
+		v, err := strconv.ParseInt(strings.TrimSpace(s), 10, 0)
+		return int(v), err
+	case kindFloat:
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 32)
+		return float32(v), err
+	case kindDouble:
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	case kindString:
+		return s, nil
+	}
+	return nil, fmt.Errorf("Unsupported type %q", kind)
+}
+
+// scanFields calls fn with the white space separated fields of every line of r, along
+// with its line number. Empty lines and lines starting with comment are skipped.
+// Errors returned by fn are wrapped in a *ParseError, unless they already are one.
+func scanFields(r io.Reader, comment string, fn func(line int, fields []string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || comment != "" && strings.HasPrefix(text, comment) {
+			continue
+		}
+		if err := fn(line, strings.Fields(text)); err != nil {
+			if _, ok := err.(*ParseError); !ok {
+				err = &ParseError{line, err}
+			}
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// LabelKey is the node Attr key holding the node labels of the formats supporting them (GML, Pajek...).
+const LabelKey = "label"
@@ -0,0 +1,63 @@
+package grapho
+
+import "encoding/json"
+
+// jsonGraph is the node-link representation of a Graph, used for JSON encoding.
+type jsonGraph struct {
+	Directed bool       `json:"directed"`
+	Nodes    []jsonNode `json:"nodes"`
+	Edges    []jsonEdge `json:"edges"`
+}
+
+type jsonNode struct {
+	ID   uint64 `json:"id"`
+	Attr Attr   `json:"attrs,omitempty"`
+}
+
+type jsonEdge struct {
+	Source uint64 `json:"source"`
+	Target uint64 `json:"target"`
+	Weight int    `json:"weight"`
+	Attr   Attr   `json:"attrs,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface, encoding the Graph in node-link format:
+//
+//	{"directed": false, "nodes": [{"id": 1, "attrs": {...}}], "edges": [{"source": 1, "target": 2, "weight": 1, "attrs": {...}}]}
+//
+// Nodes and edges are ordered by node id, so the output is deterministic.
+// In undirected graphs, every edge is encoded once.
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	doc := jsonGraph{
+		Directed: g.directed,
+		Nodes:    make([]jsonNode, 0, len(g.nodes)),
+		Edges:    []jsonEdge{},
+	}
+	for _, node := range g.sortedNodeIDs() {
+		doc.Nodes = append(doc.Nodes, jsonNode{node, g.nodes[node]})
+	}
+	for _, edge := range g.sortedEdges() {
+		doc.Edges = append(doc.Edges, jsonEdge{edge.u, edge.v, edge.Weight, edge.Attr})
+	}
+	return json.Marshal(doc)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface, decoding a Graph in the node-link
+// format written by MarshalJSON. Any previous content of the Graph is discarded.
+// Attribute values are decoded following the encoding/json rules for interface{} values
+// (i.e. numbers are decoded as float64).
+func (g *Graph) UnmarshalJSON(data []byte) error {
+	var doc jsonGraph
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	*g = *NewGraph(doc.Directed)
+	for _, node := range doc.Nodes {
+		g.AddNode(node.ID, node.Attr)
+	}
+	for _, edge := range doc.Edges {
+		g.AddEdge(edge.Source, edge.Target, edge.Weight, edge.Attr)
+	}
+	return nil
+}
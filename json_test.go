@@ -0,0 +1,54 @@
+package grapho
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGraphJSON(t *testing.T) {
+	g := NewGraph(false)
+	g.AddNode(3, Attr{"name": "Bob"})
+	g.AddEdge(2, 1, 5, Attr{"x": 1})
+	g.AddEdge(3, 1, 1, nil)
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	expected := `{"directed":false,"nodes":[{"id":1},{"id":2},{"id":3,"attrs":{"name":"Bob"}}],` +
+		`"edges":[{"source":1,"target":2,"weight":5,"attrs":{"x":1}},{"source":1,"target":3,"weight":1}]}`
+	if string(data) != expected {
+		t.Errorf("Marshal: %s\nExpected: %s", data, expected)
+	}
+
+	var read Graph
+	if err := json.Unmarshal(data, &read); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if read.IsDirected() || read.Len() != 3 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", read.IsDirected(), read.Len())
+	}
+	if attr, _ := read.Node(3); attr["name"] != "Bob" {
+		t.Errorf("Unexpected attributes for node 3: %v", attr)
+	}
+	edge, ok := read.Edge(2, 1)
+	if !ok || edge.Weight != 5 || edge.Attr["x"] != 1.0 {
+		t.Errorf("Unexpected edge 2-1: %v", edge)
+	}
+	if v := read.Validate(); len(v) != 0 {
+		t.Errorf("Unexpected violations: %v", v)
+	}
+
+	// Digraph round trip
+	data, err = json.Marshal(sampleDiGraph())
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := json.Unmarshal(data, &read); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	path, err := Search(&read, 1, 9, BreadthFirstSearch, nil)
+	if err != nil || !equalPath(path, []uint64{1, 2, 4, 7, 9}) {
+		t.Errorf("Unexpected path after round trip: %v (%v)", path, err)
+	}
+}
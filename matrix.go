@@ -0,0 +1,266 @@
+package grapho
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// COOMatrix is a sparse adjacency matrix in coordinate format: the i-th non-zero
+// entry is at row Rows[i] and column Cols[i], holding Values[i].
+type COOMatrix struct {
+	N                  int // Number of rows and columns
+	Rows, Cols, Values []int
+}
+
+// CSRMatrix is a sparse adjacency matrix in compressed sparse row format: the non-zero entries of
+// row i are held in Cols[RowPtr[i]:RowPtr[i+1]] (column) and Values[RowPtr[i]:RowPtr[i+1]] (value).
+type CSRMatrix struct {
+	N                    int // Number of rows and columns
+	RowPtr, Cols, Values []int
+}
+
+// ToAdjacencyMatrix returns the dense adjacency matrix of the Graph, where m[i][j] holds the weight
+// of the edge between the nodes at positions i and j of the returned Index. Missing edges are
+// represented with 0, thus edges with weight 0 cannot be told apart from them.
+func ToAdjacencyMatrix(g *Graph) ([][]int, *Index) {
+	x := g.Index()
+	n := x.Len()
+
+	cells := make([]int, n*n)
+	m := make([][]int, n)
+	for i := range m {
+		m[i] = cells[i*n : (i+1)*n]
+	}
+
+	for i, u := range x.ids {
+		for v, edge := range g.edges[u] {
+			if j, ok := x.Pos(v); ok {
+				m[i][j] = edge.Weight
+			}
+		}
+	}
+	return m, x
+}
+
+// ToCOO returns the sparse adjacency matrix of the Graph in coordinate format, with
+// the entries ordered by row and column. Rows and columns follow the returned Index.
+// In undirected graphs, every edge yields two symmetric entries (one for self-loops).
+func ToCOO(g *Graph) (*COOMatrix, *Index) {
+	csr, x := ToCSR(g)
+
+	m := &COOMatrix{
+		N:      csr.N,
+		Rows:   make([]int, 0, len(csr.Cols)),
+		Cols:   csr.Cols,
+		Values: csr.Values,
+	}
+	for i := 0; i < csr.N; i++ {
+		for k := csr.RowPtr[i]; k < csr.RowPtr[i+1]; k++ {
+			m.Rows = append(m.Rows, i)
+		}
+	}
+	return m, x
+}
+
+// ToCSR returns the sparse adjacency matrix of the Graph in compressed sparse row format,
+// with the entries of each row ordered by column. Rows and columns follow the returned Index.
+// In undirected graphs, every edge yields two symmetric entries (one for self-loops).
+func ToCSR(g *Graph) (*CSRMatrix, *Index) {
+	x := g.Index()
+	adj := g.adjacency(x)
+
+	m := &CSRMatrix{N: x.Len(), RowPtr: make([]int, x.Len()+1)}
+	for i, succ := range adj {
+		u := x.ID(i)
+		for _, j := range succ {
+			m.Cols = append(m.Cols, j)
+			m.Values = append(m.Values, g.edges[u][x.ID(j)].Weight)
+		}
+		m.RowPtr[i+1] = len(m.Cols)
+	}
+	return m, x
+}
+
+// FromAdjacencyMatrix builds a Graph from a square adjacency matrix, where every non-zero
+// m[i][j] becomes an edge with that weight, between the nodes at positions i and j of the Index.
+// If x is nil, the node at position i is given the id i+1. Undirected graphs require a symmetric matrix.
+func FromAdjacencyMatrix(m [][]int, x *Index, directed bool) (*Graph, error) {
+	n := len(m)
+	if x == nil {
+		ids := make([]uint64, n)
+		for i := range ids {
+			ids[i] = uint64(i + 1)
+		}
+		x = NewIndex(ids)
+	}
+	if x.Len() != n {
+		return nil, fmt.Errorf("Index holds %d nodes, matrix has %d rows", x.Len(), n)
+	}
+
+	// Validate every row first, as the symmetry check reads the whole matrix
+	for i, row := range m {
+		if len(row) != n {
+			return nil, fmt.Errorf("Row %d has %d columns. Expected %d", i, len(row), n)
+		}
+	}
+
+	g := NewGraph(directed)
+	for i := 0; i < n; i++ {
+		g.AddNode(x.ID(i), nil)
+	}
+	for i, row := range m {
+		for j, weight := range row {
+			if !directed && m[j][i] != weight {
+				return nil, fmt.Errorf("Matrix is not symmetric at %d,%d", i, j)
+			}
+			if weight != 0 && (directed || i <= j) {
+				g.AddEdge(x.ID(i), x.ID(j), weight, nil)
+			}
+		}
+	}
+	return g, nil
+}
+
+const matrixMarketBanner = "%%MatrixMarket"
+
+// WriteMatrixMarket writes the adjacency matrix of the Graph in Matrix Market coordinate format,
+// using Edge.Weight as the (integer) matrix value. Directed graphs are written with general storage,
+// and undirected graphs with symmetric storage (lower triangle entries only).
+// Rows and columns follow the Graph Index order, starting at 1.
+func WriteMatrixMarket(w io.Writer, g *Graph) error {
+	csr, _ := ToCSR(g)
+
+	type entry struct{ i, j, value int }
+	var entries []entry
+	for i := 0; i < csr.N; i++ {
+		for k := csr.RowPtr[i]; k < csr.RowPtr[i+1]; k++ {
+			if j := csr.Cols[k]; g.directed || i >= j {
+				entries = append(entries, entry{i + 1, j + 1, csr.Values[k]})
+			}
+		}
+	}
+
+	symmetry := "symmetric"
+	if g.directed {
+		symmetry = "general"
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%s matrix coordinate integer %s\n", matrixMarketBanner, symmetry)
+	fmt.Fprintf(bw, "%d %d %d\n", csr.N, csr.N, len(entries))
+	for _, e := range entries {
+		fmt.Fprintf(bw, "%d %d %d\n", e.i, e.j, e.value)
+	}
+	return bw.Flush()
+}
+
+// ReadMatrixMarket builds a Graph from a square matrix in Matrix Market coordinate format.
+// Row/column i is bound to the node with id i (starting at 1), and every entry becomes an edge.
+// General matrices yield directed graphs, and symmetric matrices undirected ones.
+// Values must be integers (real values must have no fractional part), and are read as Edge.Weight.
+// Pattern matrices yield edges with a weight of 1. Dense (array) storage, complex values and
+// skew-symmetric or hermitian matrices are not supported. Matrices declaring over 2^20 more rows than
+// their entries reference are rejected, instead of allocating them.
+func ReadMatrixMarket(r io.Reader) (*Graph, error) {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	next := func() ([]string, error) {
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" || strings.HasPrefix(text, "%") {
+				continue
+			}
+			return strings.Fields(text), nil
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, &ParseError{line, io.ErrUnexpectedEOF}
+	}
+
+	// Banner
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, &ParseError{1, io.ErrUnexpectedEOF}
+	}
+	line++
+	banner := strings.Fields(strings.ToLower(scanner.Text()))
+	if len(banner) != 5 || banner[0] != strings.ToLower(matrixMarketBanner) || banner[1] != "matrix" {
+		return nil, &ParseError{line, errors.New("Invalid Matrix Market banner")}
+	}
+	format, field, symmetry := banner[2], banner[3], banner[4]
+	if format != "coordinate" {
+		return nil, &ParseError{line, fmt.Errorf("Unsupported format %q", format)}
+	}
+	if field != "integer" && field != "real" && field != "pattern" {
+		return nil, &ParseError{line, fmt.Errorf("Unsupported field %q", field)}
+	}
+	if symmetry != "general" && symmetry != "symmetric" {
+		return nil, &ParseError{line, fmt.Errorf("Unsupported symmetry %q", symmetry)}
+	}
+
+	// Size
+	fields, err := next()
+	if err != nil {
+		return nil, err
+	}
+	size := make([]int, len(fields))
+	for i, f := range fields {
+		if size[i], err = strconv.Atoi(f); err != nil || size[i] < 0 {
+			return nil, &ParseError{line, fmt.Errorf("Invalid size %q", f)}
+		}
+	}
+	if len(size) != 3 || size[0] != size[1] {
+		return nil, &ParseError{line, errors.New("Expected square matrix size: rows columns entries")}
+	}
+	n, entries := size[0], size[2]
+	sizeLine := line
+
+	g := NewGraph(symmetry == "general")
+
+	for k := 0; k < entries; k++ {
+		fields, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if field == "pattern" && len(fields) != 2 || field != "pattern" && len(fields) != 3 {
+			return nil, &ParseError{line, fmt.Errorf("Unexpected number of fields: %d", len(fields))}
+		}
+
+		i, erri := strconv.Atoi(fields[0])
+		j, errj := strconv.Atoi(fields[1])
+		if erri != nil || errj != nil || i < 1 || i > n || j < 1 || j > n {
+			return nil, &ParseError{line, fmt.Errorf("Invalid coordinates %s %s", fields[0], fields[1])}
+		}
+
+		weight := 1
+		switch field {
+		case "integer":
+			if weight, err = strconv.Atoi(fields[2]); err != nil {
+				return nil, &ParseError{line, fmt.Errorf("Invalid value %q", fields[2])}
+			}
+		case "real":
+			f, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil || f != math.Trunc(f) {
+				return nil, &ParseError{line, fmt.Errorf("Invalid integer value %q", fields[2])}
+			}
+			weight = int(f)
+		}
+		g.AddEdge(uint64(i), uint64(j), weight, nil)
+	}
+
+	// Nodes are added once the entries are read, not to trust the declared size blindly
+	if err := addDeclaredNodes(g, n); err != nil {
+		return nil, &ParseError{sizeLine, err}
+	}
+
+	return g, nil
+}
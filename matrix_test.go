@@ -0,0 +1,133 @@
+package grapho
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAdjacencyMatrix(t *testing.T) {
+	g := NewGraph(true)
+	g.AddEdge(10, 30, 2, nil)
+	g.AddEdge(30, 20, 3, nil)
+	g.AddEdge(20, 20, 4, nil)
+
+	m, x := ToAdjacencyMatrix(g)
+	expected := [][]int{{0, 0, 2}, {0, 4, 0}, {0, 3, 0}}
+	for i := range expected {
+		if !equalInts(m[i], expected[i]) {
+			t.Errorf("Row %d: %v. Expected %v", i, m[i], expected[i])
+		}
+	}
+	if !EqualsIntSlice(x.IDs(), []uint64{10, 20, 30}) {
+		t.Errorf("Unexpected ordering: %v", x.IDs())
+	}
+
+	coo, _ := ToCOO(g)
+	if !equalInts(coo.Rows, []int{0, 1, 2}) || !equalInts(coo.Cols, []int{2, 1, 1}) || !equalInts(coo.Values, []int{2, 4, 3}) {
+		t.Errorf("Unexpected COO matrix: %+v", coo)
+	}
+	csr, _ := ToCSR(g)
+	if !equalInts(csr.RowPtr, []int{0, 1, 2, 3}) || !equalInts(csr.Cols, []int{2, 1, 1}) {
+		t.Errorf("Unexpected CSR matrix: %+v", csr)
+	}
+
+	read, err := FromAdjacencyMatrix(m, x, true)
+	if err != nil {
+		t.Fatalf("FromAdjacencyMatrix: %v", err)
+	}
+	for _, e := range g.sortedEdges() {
+		if edge, ok := read.Edge(e.u, e.v); !ok || edge.Weight != e.Weight {
+			t.Errorf("Unexpected edge %d-%d: %v", e.u, e.v, edge)
+		}
+	}
+
+	if _, err := FromAdjacencyMatrix(m, nil, false); err == nil {
+		t.Errorf("FromAdjacencyMatrix: Did not get expected error with asymmetric matrix")
+	}
+	read, err = FromAdjacencyMatrix([][]int{{0, 1}, {1, 0}}, nil, false)
+	if err != nil {
+		t.Fatalf("FromAdjacencyMatrix: %v", err)
+	}
+	testEdgeExists(t, read, 1, 2, true)
+
+	ragged := [][]int{{0, 0, 0}, {0, 0, 0}, {0}}
+	for _, directed := range []bool{true, false} {
+		if _, err := FromAdjacencyMatrix(ragged, nil, directed); err == nil {
+			t.Errorf("FromAdjacencyMatrix: Did not get expected error with ragged matrix (directed=%v)", directed)
+		}
+	}
+}
+
+func TestMatrixMarket(t *testing.T) {
+	g := NewGraph(false)
+	g.AddEdge(1, 2, 5, nil)
+	g.AddEdge(2, 3, -1, nil)
+	g.AddEdge(3, 3, 2, nil)
+
+	var buf bytes.Buffer
+	if err := WriteMatrixMarket(&buf, g); err != nil {
+		t.Fatalf("WriteMatrixMarket: %v", err)
+	}
+	expected := "%%MatrixMarket matrix coordinate integer symmetric\n3 3 3\n2 1 5\n3 2 -1\n3 3 2\n"
+	if buf.String() != expected {
+		t.Errorf("WriteMatrixMarket: %q. Expected %q", buf.String(), expected)
+	}
+
+	read, err := ReadMatrixMarket(&buf)
+	if err != nil {
+		t.Fatalf("ReadMatrixMarket: %v", err)
+	}
+	if read.IsDirected() || read.Len() != 3 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", read.IsDirected(), read.Len())
+	}
+	for _, e := range g.sortedEdges() {
+		if edge, ok := read.Edge(e.u, e.v); !ok || edge.Weight != e.Weight {
+			t.Errorf("Unexpected edge %d-%d: %v", e.u, e.v, edge)
+		}
+	}
+
+	src := `%%MatrixMarket matrix coordinate real general
+% comment
+4 4 2
+1 2 3.0
+4 1 1e1
+`
+	read, err = ReadMatrixMarket(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadMatrixMarket: %v", err)
+	}
+	if !read.IsDirected() || read.Len() != 4 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", read.IsDirected(), read.Len())
+	}
+	if edge, ok := read.Edge(4, 1); !ok || edge.Weight != 10 {
+		t.Errorf("Unexpected edge 4-1: %v", edge)
+	}
+	testEdgeExists(t, read, 2, 1, false)
+
+	for src, line := range map[string]int{
+		"%%MatrixMarket matrix array real general\n2 2\n":                          1,
+		"%%MatrixMarket matrix coordinate real general\n2 3 1\n":                   2,
+		"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 3 1":              3,
+		"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 .5":             3,
+		"%%MatrixMarket matrix coordinate pattern general\n2 2 2\n1 2":             3,
+		"%%MatrixMarket matrix coordinate real general\n2000000000 2000000000 0\n": 2,
+	} {
+		_, err := ReadMatrixMarket(strings.NewReader(src))
+		if pe, ok := err.(*ParseError); !ok || pe.Line != line {
+			t.Errorf("ReadMatrixMarket(%q): Expected error on line %d, got %v", src, line, err)
+		}
+	}
+}
+
+func equalInts(x, y []int) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,265 @@
+// METIS graph and partition files
+// http://glaros.dtc.umn.edu/gkhome/fetch/sw/metis/manual.pdf
+
+package grapho
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// metisWeights returns the vertex weights of a node, held in an int or []int attribute.
+func metisWeights(v interface{}) ([]int, bool) {
+	switch w := v.(type) {
+	case int:
+		return []int{w}, true
+	case []int:
+		return w, len(w) > 0
+	}
+	return nil, false
+}
+
+// WriteMETIS writes the Graph as a METIS graph file, to be partitioned by METIS or compatible tools.
+// The graph must be undirected, without self-loops, and with positive edge weights, which are written
+// as the METIS edge weights. If vertexWeightKey is not empty, every node must hold its vertex weight
+// in that attribute, either an int or, for multiple balancing constraints, an []int of the same length
+// for every node. Vertices are numbered 1..n following the returned Index, which binds them back to the Graph nodes.
+func WriteMETIS(w io.Writer, g *Graph, vertexWeightKey string) (*Index, error) {
+	if g.directed {
+		return nil, errors.New("Graph must be undirected")
+	}
+	x := g.Index()
+	adj := g.adjacency(x)
+
+	ncon := 0
+	weights := make([][]int, x.Len())
+	if vertexWeightKey != "" {
+		for i, node := range x.ids {
+			vw, ok := metisWeights(g.nodes[node][vertexWeightKey])
+			if !ok {
+				return nil, fmt.Errorf("Node %d: missing or invalid vertex weight %v", node, g.nodes[node][vertexWeightKey])
+			}
+			if i == 0 {
+				ncon = len(vw)
+			} else if len(vw) != ncon {
+				return nil, fmt.Errorf("Node %d: expected %d vertex weights, found %d", node, ncon, len(vw))
+			}
+			for _, v := range vw {
+				if v < 0 {
+					return nil, fmt.Errorf("Node %d: negative vertex weight %d", node, v)
+				}
+			}
+			weights[i] = vw
+		}
+	}
+
+	m := 0
+	for i, succ := range adj {
+		u := x.ID(i)
+		for _, j := range succ {
+			if i == j {
+				return nil, fmt.Errorf("Self-loops are not supported: node %d", u)
+			}
+			if weight := g.edges[u][x.ID(j)].Weight; weight <= 0 {
+				return nil, fmt.Errorf("Edge %d-%d: weight must be positive, found %d", u, x.ID(j), weight)
+			}
+		}
+		m += len(succ)
+	}
+
+	bw := bufio.NewWriter(w)
+	switch {
+	case ncon == 0:
+		fmt.Fprintf(bw, "%d %d 001\n", x.Len(), m/2)
+	case ncon == 1:
+		fmt.Fprintf(bw, "%d %d 011\n", x.Len(), m/2)
+	default:
+		fmt.Fprintf(bw, "%d %d 011 %d\n", x.Len(), m/2, ncon)
+	}
+
+	fields := make([]string, 0)
+	for i, succ := range adj {
+		fields = fields[:0]
+		for _, v := range weights[i] {
+			fields = append(fields, strconv.Itoa(v))
+		}
+		u := x.ID(i)
+		for _, j := range succ {
+			fields = append(fields, strconv.Itoa(j+1), strconv.Itoa(g.edges[u][x.ID(j)].Weight))
+		}
+		bw.WriteString(strings.Join(fields, " "))
+		bw.WriteByte('\n')
+	}
+	return x, bw.Flush()
+}
+
+// ReadMETIS builds an undirected Graph from a METIS graph file, giving vertex i the node id i.
+// Edge weights, if present, are read as Edge.Weight (edges are given a weight of 1 otherwise).
+// If vertexWeightKey is not empty, vertex weights, if present, are stored in that attribute, as an int,
+// or as an []int if the file declares multiple balancing constraints. Vertex sizes are ignored.
+// The adjacency lists must be symmetric, and hold as many edges as declared in the header.
+func ReadMETIS(r io.Reader, vertexWeightKey string) (*Graph, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 256*1024*1024)
+	line := 0
+
+	// next returns the fields of the next line which is not a comment. Vertex lines may be empty.
+	next := func() ([]string, bool) {
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if strings.HasPrefix(text, "%") {
+				continue
+			}
+			return strings.Fields(text), true
+		}
+		return nil, false
+	}
+
+	// Header: n m [fmt [ncon]]
+	var header []string
+	for {
+		fields, ok := next()
+		if !ok {
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, errors.New("Missing header line")
+		}
+		if len(fields) > 0 {
+			header = fields
+			break
+		}
+	}
+	values, err := parseInts(header)
+	if err != nil {
+		return nil, &ParseError{line, err}
+	}
+	if len(values) < 2 || len(values) > 4 || values[0] < 0 || values[1] < 0 {
+		return nil, &ParseError{line, errors.New("Expected header line: n m [fmt [ncon]]")}
+	}
+	n, m := values[0], values[1]
+
+	format := "000"
+	if len(header) > 2 {
+		format = fmt.Sprintf("%03s", header[2])
+	}
+	if len(format) != 3 || strings.Trim(format, "01") != "" {
+		return nil, &ParseError{line, fmt.Errorf("Invalid format %q", header[2])}
+	}
+	sizes, vertexWeights, edgeWeights := format[0] == '1', format[1] == '1', format[2] == '1'
+	ncon := 0
+	if vertexWeights {
+		ncon = 1
+		if len(values) > 3 {
+			if ncon = values[3]; ncon < 1 {
+				return nil, &ParseError{line, fmt.Errorf("Invalid number of constraints %d", ncon)}
+			}
+		}
+	}
+
+	// Nodes are added as their vertex lines are read, so that the header alone does not allocate them
+	g := NewGraph(false)
+	arcs, edges := 0, 0
+	listed := make(map[int]int) // last vertex listing each vertex as a neighbor
+	for u := 1; u <= n; u++ {
+		fields, ok := next()
+		if !ok {
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+			return nil, &ParseError{line, fmt.Errorf("Expected %d vertex lines, found %d", n, u-1)}
+		}
+		values, err := parseInts(fields)
+		if err != nil {
+			return nil, &ParseError{line, err}
+		}
+		g.AddNodeIfAbsent(uint64(u), nil)
+
+		if sizes {
+			if len(values) == 0 {
+				return nil, &ParseError{line, errors.New("Missing vertex size")}
+			}
+			values = values[1:]
+		}
+		if len(values) < ncon {
+			return nil, &ParseError{line, fmt.Errorf("Expected %d vertex weights", ncon)}
+		}
+		if vertexWeightKey != "" && ncon == 1 {
+			g.SetNodeAttr(uint64(u), vertexWeightKey, values[0])
+		} else if vertexWeightKey != "" && ncon > 1 {
+			g.SetNodeAttr(uint64(u), vertexWeightKey, append([]int(nil), values[:ncon]...))
+		}
+		values = values[ncon:]
+
+		step := 1
+		if edgeWeights {
+			step = 2
+		}
+		if len(values)%step != 0 {
+			return nil, &ParseError{line, errors.New("Expected vertex and weight pairs")}
+		}
+		for k := 0; k < len(values); k += step {
+			v, weight := values[k], 1
+			if edgeWeights {
+				weight = values[k+1]
+			}
+			if v < 1 || v > n || v == u {
+				return nil, &ParseError{line, fmt.Errorf("Invalid vertex %d", v)}
+			}
+			if listed[v] == u {
+				return nil, &ParseError{line, fmt.Errorf("Duplicated vertex %d", v)}
+			}
+			listed[v] = u
+			if edge, ok := g.Edge(uint64(u), uint64(v)); ok && v < u {
+				if edge.Weight != weight {
+					return nil, &ParseError{line, fmt.Errorf("Edge %d-%d: asymmetric weight", u, v)}
+				}
+			} else {
+				g.AddEdge(uint64(u), uint64(v), weight, nil)
+				edges++
+			}
+			arcs++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// Every edge must have been listed once by each of its endpoints
+	if arcs != 2*edges || edges != m {
+		return nil, fmt.Errorf("Expected %d edges in symmetric adjacency lists", m)
+	}
+	return g, nil
+}
+
+// ReadMETISPartition reads a partition file, as written by METIS for the given Index, returning the
+// partition of every node. Line i holds the partition of vertex i, that is, the node at position i-1 of the Index.
+func ReadMETISPartition(r io.Reader, x *Index) (map[uint64]int, error) {
+	parts := make(map[uint64]int, x.Len())
+	err := scanFields(r, "%", func(line int, fields []string) error {
+		if len(parts) == x.Len() {
+			return fmt.Errorf("Expected %d lines", x.Len())
+		}
+		if len(fields) != 1 {
+			return errors.New("Expected a single partition number")
+		}
+		part, err := strconv.Atoi(fields[0])
+		if err != nil || part < 0 {
+			return fmt.Errorf("Invalid partition %q", fields[0])
+		}
+		parts[x.ID(len(parts))] = part
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) != x.Len() {
+		return nil, fmt.Errorf("Expected %d lines, found %d", x.Len(), len(parts))
+	}
+	return parts, nil
+}
@@ -0,0 +1,115 @@
+package grapho
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteMETIS(t *testing.T) {
+	g := NewGraph(false)
+	g.AddNode(10, Attr{"load": 2})
+	g.AddNode(20, Attr{"load": 1})
+	g.AddNode(30, Attr{"load": 3})
+	g.AddNode(40, Attr{"load": 0})
+	g.AddEdge(10, 20, 5, nil)
+	g.AddEdge(20, 30, 1, nil)
+
+	var buf bytes.Buffer
+	x, err := WriteMETIS(&buf, g, "load")
+	if err != nil {
+		t.Fatalf("WriteMETIS: %v", err)
+	}
+	expected := "4 2 011\n2 2 5\n1 1 5 3 1\n3 2 1\n0\n"
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+	if x.ID(0) != 10 || x.ID(3) != 40 {
+		t.Errorf("Unexpected index: %v", x.IDs())
+	}
+
+	h, err := ReadMETIS(&buf, "load")
+	if err != nil {
+		t.Fatalf("ReadMETIS: %v", err)
+	}
+	if h.Len() != 4 {
+		t.Fatalf("Unexpected number of nodes: %d", h.Len())
+	}
+	if attr, _ := h.Node(3); attr["load"] != 3 {
+		t.Errorf("Unexpected attributes for node 3: %v", attr)
+	}
+	if edge, ok := h.Edge(2, 1); !ok || edge.Weight != 5 {
+		t.Errorf("Unexpected edge 2-1: %v", edge)
+	}
+
+	parts, err := ReadMETISPartition(strings.NewReader("0\n1\n1\n0\n"), x)
+	if err != nil {
+		t.Fatalf("ReadMETISPartition: %v", err)
+	}
+	if parts[10] != 0 || parts[20] != 1 || parts[30] != 1 || parts[40] != 0 {
+		t.Errorf("Unexpected partitions: %v", parts)
+	}
+	if _, err := ReadMETISPartition(strings.NewReader("0\n1\n"), x); err == nil {
+		t.Error("Expected error reading a short partition file")
+	}
+
+	if _, err := WriteMETIS(&buf, sampleDiGraph(), ""); err == nil {
+		t.Error("Expected error writing a directed graph")
+	}
+	g.AddEdge(30, 40, 0, nil)
+	if _, err := WriteMETIS(&buf, g, ""); err == nil {
+		t.Error("Expected error writing a zero weight edge")
+	}
+	g.AddEdge(30, 40, 1, nil)
+	g.AddNode(50, nil)
+	if _, err := WriteMETIS(&buf, g, "load"); err == nil {
+		t.Error("Expected error writing a node without vertex weight")
+	}
+}
+
+func TestReadMETIS(t *testing.T) {
+	src := `% multi-constraint, with vertex sizes
+3 2 111 2
+1 4 5 2 7
+1 1 1 1 7 3 2
+2 0 0 2 2
+`
+	g, err := ReadMETIS(strings.NewReader(src), "w")
+	if err != nil {
+		t.Fatalf("ReadMETIS: %v", err)
+	}
+	attr, _ := g.Node(1)
+	if w, ok := attr["w"].([]int); !ok || !equalInts(w, []int{4, 5}) {
+		t.Errorf("Unexpected attributes for node 1: %v", attr)
+	}
+	if edge, ok := g.Edge(1, 2); !ok || edge.Weight != 7 {
+		t.Errorf("Unexpected edge 1-2: %v", edge)
+	}
+	if edge, ok := g.Edge(3, 2); !ok || edge.Weight != 2 {
+		t.Errorf("Unexpected edge 3-2: %v", edge)
+	}
+
+	g, err = ReadMETIS(strings.NewReader("3 1\n2\n1\n\n"), "")
+	if err != nil {
+		t.Fatalf("ReadMETIS: %v", err)
+	}
+	if g.Len() != 3 || len(g.sortedEdges()) != 1 {
+		t.Errorf("Unexpected graph: %d nodes, %d edges", g.Len(), len(g.sortedEdges()))
+	}
+
+	for _, src := range []string{
+		"",
+		"2 1\n2\n",
+		"2 1\n2\n2\n",
+		"3 1\n2\n3\n\n",
+		"2 1 001\n2 3\n1 4\n",
+		"2 1 2\n2\n1\n",
+		"2 1\n\n1 1\n",
+		"4 2\n2\n1\n\n3 1\n",
+		"2000000000 0\n",
+	} {
+		if _, err := ReadMETIS(strings.NewReader(src), ""); err == nil {
+			t.Errorf("Expected error reading %q", src)
+		}
+	}
+}
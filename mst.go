@@ -16,18 +16,38 @@ const (
 	// TODO: Kruskal
 )
 
-// Connected returns whether the Graph is fully connected or not.
+// IsConnected returns whether the Graph is fully connected or not.
+// For digraphs, every node must be reachable from the lowest one.
 func IsConnected(g *Graph) bool {
-	start := g.Nodes()[0] // choose randomly the first node
+	x := g.Index()
+	if x.Len() == 0 {
+		return true
+	}
+
 	// Run a DFS to check if we can reach all the nodes in the Graph
-	closedSet := traverse(g, start, start, DepthFirstSearch, nil)
-	return len(closedSet) == g.Len()
+	visited := container.NewBitset(x.Len())
+	visited.Set(0)
+	stack := []int{0}
+	count := 1
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for v := range g.edges[x.ID(i)] {
+			if j, ok := x.Pos(v); ok && !visited.Test(j) {
+				visited.Set(j)
+				stack = append(stack, j)
+				count++
+			}
+		}
+	}
+	return count == x.Len()
 }
 
-// mstState is the struct to be stored in the heap, holding a node and its parent
+// mstState is the struct to be stored in the heap, holding a node and its parent, as Index positions.
 // The priority of the item is the edge weight between them
 type mstState struct {
-	node, parent uint64
+	node, parent int
 }
 
 // MinimumSpanningTree calculates the MST using the specified algorithm
@@ -52,40 +72,42 @@ func PrimMst(graph *Graph) (*Graph, error) {
 	mst := NewGraph(false)
 	pq := &container.PQueue{} // PQueue will determine which is the next node to add
 
+	x := graph.Index()
+	adj := graph.adjacency(x)
+	inMst := container.NewBitset(x.Len())
+
 	// expand adds the given node to the MST, and will recompute the PQueue priorities for its successors
-	expand := func(node, parent uint64) {
+	expand := func(i, parent int) {
 		// Add node to the MST
-		attr, _ := graph.Node(node)
-		mst.AddNode(node, attr) // TODO: Deep copy of *Attr instead?
-
-		if node != parent {
-			// node == parent means that node has no parent.
-			edge, _ := graph.Edge(node, parent)
-			mst.AddEdge(node, parent, edge.Weight, edge.Attr)
+		node := x.ID(i)
+		inMst.Set(i)
+		mst.AddNode(node, graph.nodes[node]) // TODO: Deep copy of *Attr instead?
+
+		if i != parent {
+			// i == parent means that node has no parent.
+			edge := graph.edges[node][x.ID(parent)]
+			mst.AddEdge(node, x.ID(parent), edge.Weight, edge.Attr)
 		}
 
 		// recompute priorities, if necessary
-		succs, _ := graph.Neighbors(node)
-		for _, succ := range succs {
-			if _, ok := mst.Node(succ); !ok { // Skip the node if it's already in the mst Graph
-				if edge, ok := graph.Edge(node, succ); ok {
-					state := &mstState{succ, node}
-					pq.Push(state, edge.Weight)
-				}
+		for _, j := range adj[i] {
+			if !inMst.Test(j) { // Skip the node if it's already in the mst Graph
+				pq.Push(&mstState{j, i}, graph.edges[node][x.ID(j)].Weight)
 			}
 		}
 	}
 
-	node := graph.Nodes()[0] // choose randomly the first node
-	expand(node, node)
+	if x.Len() == 0 {
+		return mst, nil
+	}
+	expand(0, 0) // start with the lowest node
 
-	for pq.Len() > 0 { // NOTE: this invariant is not be the most efficient one. Compare the number of nodes instead
+	for pq.Len() > 0 && mst.Len() < x.Len() {
 		state := pq.Pop().(*mstState)
-		node, parent := state.node, state.parent
 
 		// Only consider non expanded nodes (not present in mst)
-		if _, ok := mst.Node(node); !ok {
-			expand(node, parent)
+		if !inMst.Test(state.node) {
+			expand(state.node, state.parent)
 		}
 	}
 
@@ -80,3 +80,18 @@ func TestMinimumSpanningTree(t *testing.T) {
 	testEdgeExists(t, mst, 2, 6, false)
 	testEdgeExists(t, mst, 4, 6, false)
 }
+
+func TestIsConnected(t *testing.T) {
+	if !IsConnected(NewGraph(false)) {
+		t.Errorf("Empty graph should be connected")
+	}
+
+	g := sampleGraph()
+	if !IsConnected(g) {
+		t.Errorf("Graph should be connected")
+	}
+	g.AddNode(10, nil)
+	if IsConnected(g) {
+		t.Errorf("Graph should not be connected")
+	}
+}
@@ -0,0 +1,459 @@
+// Neo4j bulk import CSV files
+// https://neo4j.com/docs/operations-manual/current/tools/neo4j-admin/neo4j-admin-import/
+
+package grapho
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Attr keys holding the Neo4j node labels ([]string) and relationship type (string).
+const (
+	Neo4jLabelsKey = "labels"
+	Neo4jTypeKey   = "type"
+)
+
+// Neo4jWeightKey is the relationship property holding Edge.Weight.
+const Neo4jWeightKey = "weight"
+
+// Neo4jDefaultType is the type given to the relationships of edges without a Neo4jTypeKey attribute.
+const Neo4jDefaultType = "RELATED"
+
+// neo4jArrayDelimiter separates the values of array properties and labels.
+const neo4jArrayDelimiter = ";"
+
+// Special header fields of node and relationship files.
+const (
+	neo4jID      = "ID"
+	neo4jLabel   = "LABEL"
+	neo4jStartID = "START_ID"
+	neo4jEndID   = "END_ID"
+	neo4jType    = "TYPE"
+	neo4jIgnore  = "IGNORE"
+)
+
+// neo4jTypes binds the Neo4j property types to the attribute value types.
+var neo4jTypes = map[string]string{
+	"boolean": kindBoolean,
+	"byte":    kindInt,
+	"short":   kindInt,
+	"int":     kindInt,
+	"long":    kindLong,
+	"float":   kindFloat,
+	"double":  kindDouble,
+	"char":    kindString,
+	"string":  kindString,
+}
+
+// neo4jColumn is a parsed header field, i.e. "name:int[]" or ":START_ID(Group)".
+type neo4jColumn struct {
+	name  string // property name, if any
+	kind  string // attribute value type, or one of the special fields
+	array bool
+}
+
+// parseNeo4jHeader parses the header of a node or relationship file.
+func parseNeo4jHeader(header []string) ([]neo4jColumn, error) {
+	columns := make([]neo4jColumn, len(header))
+	for i, field := range header {
+		name, typ := field, "string"
+		if sep := strings.LastIndex(field, ":"); sep >= 0 {
+			name, typ = field[:sep], field[sep+1:]
+		}
+		// ID spaces are not supported: ids must be unique among all nodes
+		if p := strings.IndexByte(typ, '('); p >= 0 && strings.HasSuffix(typ, ")") {
+			typ = typ[:p]
+		}
+
+		c := neo4jColumn{name: name}
+		switch typ {
+		case neo4jID, neo4jLabel, neo4jStartID, neo4jEndID, neo4jType, neo4jIgnore:
+			c.kind = typ
+		default:
+			c.array = strings.HasSuffix(typ, "[]")
+			kind, ok := neo4jTypes[strings.ToLower(strings.TrimSuffix(typ, "[]"))]
+			if !ok {
+				return nil, fmt.Errorf("Unsupported type %q in header field %q", typ, field)
+			}
+			if name == "" {
+				return nil, fmt.Errorf("Missing property name in header field %q", field)
+			}
+			c.kind = kind
+		}
+		columns[i] = c
+	}
+	return columns, nil
+}
+
+// parseValue parses a property value, splitting arrays into typed slices.
+func (c neo4jColumn) parseValue(s string) (interface{}, error) {
+	if !c.array {
+		return parseKind(c.kind, s)
+	}
+
+	items := strings.Split(s, neo4jArrayDelimiter)
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		v, err := parseKind(c.kind, item)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+
+	switch c.kind {
+	case kindBoolean:
+		a := make([]bool, len(values))
+		for i, v := range values {
+			a[i] = v.(bool)
+		}
+		return a, nil
+	case kindInt, kindLong:
+		a := make([]int, len(values))
+		for i, v := range values {
+			a[i] = v.(int)
+		}
+		return a, nil
+	case kindFloat:
+		a := make([]float32, len(values))
+		for i, v := range values {
+			a[i] = v.(float32)
+		}
+		return a, nil
+	case kindDouble:
+		a := make([]float64, len(values))
+		for i, v := range values {
+			a[i] = v.(float64)
+		}
+		return a, nil
+	}
+	return items, nil
+}
+
+// neo4jRecords reads a CSV file with a typed header, calling fn for every record.
+func neo4jRecords(r io.Reader, fn func(columns []neo4jColumn, record []string) error) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return errors.New("Missing header line")
+	} else if err != nil {
+		return err
+	}
+	columns, err := parseNeo4jHeader(header)
+	if err != nil {
+		return &ParseError{1, err}
+	}
+
+	cr.FieldsPerRecord = len(columns)
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			if pe, ok := err.(*csv.ParseError); ok {
+				return &ParseError{pe.Line, pe.Err}
+			}
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		if err := fn(columns, record); err != nil {
+			return &ParseError{line, err}
+		}
+	}
+}
+
+// ReadNeo4jCSV builds a directed Graph from a pair of Neo4j bulk import files: a node file, with an :ID
+// column, and a relationship file, with :START_ID and :END_ID columns. Typed properties (i.e. "age:int"
+// or "tags:string[]") are stored in Attr, with the types used by ReadGraphML, and arrays as typed slices
+// (int and long arrays as []int).
+// Empty values are skipped. Node labels are stored as an []string in the Neo4jLabelsKey attribute, and
+// relationship types as a string in the Neo4jTypeKey attribute. The Neo4jWeightKey property, which must be
+// an int or long, is read as Edge.Weight instead. Relationships without it are given a weight of 1.
+// A named ID column (i.e. "id:ID") is stored as a string property too. ID spaces are not supported.
+// Node ids are bound as in ReadDOT. The returned map binds each Neo4j node id to its node id.
+func ReadNeo4jCSV(nodes, relationships io.Reader) (*Graph, map[string]uint64, error) {
+	type nodeRecord struct {
+		id   string
+		attr Attr
+	}
+	var records []nodeRecord
+	var names []string
+
+	err := neo4jRecords(nodes, func(columns []neo4jColumn, record []string) error {
+		node := nodeRecord{attr: NewAttr()}
+		hasID := false
+		for i, c := range columns {
+			value := record[i]
+			switch c.kind {
+			case neo4jID:
+				node.id, hasID = value, true
+				if c.name != "" {
+					node.attr[c.name] = value
+				}
+			case neo4jLabel:
+				if value != "" {
+					node.attr[Neo4jLabelsKey] = strings.Split(value, neo4jArrayDelimiter)
+				}
+			case neo4jIgnore:
+			case neo4jStartID, neo4jEndID, neo4jType:
+				return fmt.Errorf("Unexpected :%s column in node file", c.kind)
+			default:
+				if value == "" {
+					continue
+				}
+				v, err := c.parseValue(value)
+				if err != nil {
+					return fmt.Errorf("Property %q: invalid value %q", c.name, value)
+				}
+				node.attr[c.name] = v
+			}
+		}
+		if !hasID {
+			return errors.New("Missing :ID column")
+		}
+		records = append(records, node)
+		names = append(names, node.id)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := assignIDs(names)
+	if len(ids) != len(names) {
+		return nil, nil, errors.New("Duplicated node ids")
+	}
+
+	g := NewGraph(true)
+	for _, node := range records {
+		g.AddNode(ids[node.id], node.attr)
+	}
+
+	err = neo4jRecords(relationships, func(columns []neo4jColumn, record []string) error {
+		var start, end *uint64
+		weight, attr := 1, NewAttr()
+		for i, c := range columns {
+			value := record[i]
+			switch c.kind {
+			case neo4jStartID, neo4jEndID:
+				id, ok := ids[value]
+				if !ok {
+					return fmt.Errorf("Unknown node %q", value)
+				}
+				if c.kind == neo4jStartID {
+					start = &id
+				} else {
+					end = &id
+				}
+			case neo4jType:
+				if value != "" {
+					attr[Neo4jTypeKey] = value
+				}
+			case neo4jIgnore:
+			case neo4jID, neo4jLabel:
+				return fmt.Errorf("Unexpected :%s column in relationship file", c.kind)
+			default:
+				if value == "" {
+					continue
+				}
+				v, err := c.parseValue(value)
+				if err != nil {
+					return fmt.Errorf("Property %q: invalid value %q", c.name, value)
+				}
+				if c.name != Neo4jWeightKey {
+					attr[c.name] = v
+					continue
+				}
+				w, ok := v.(int)
+				if !ok {
+					return fmt.Errorf("Property %q must be an int or long", Neo4jWeightKey)
+				}
+				weight = w
+			}
+		}
+		if start == nil || end == nil {
+			return errors.New("Missing :START_ID or :END_ID column")
+		}
+		g.AddEdge(*start, *end, weight, attr)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, ids, nil
+}
+
+// neo4jKind returns the Neo4j type of an attribute value, and the attribute value type of its elements.
+func neo4jKind(v interface{}) (typ, kind string) {
+	switch v.(type) {
+	case []bool:
+		return "boolean[]", kindBoolean
+	case []int, []int64:
+		return "long[]", kindLong
+	case []float32:
+		return "float[]", kindFloat
+	case []float64:
+		return "double[]", kindDouble
+	case []string:
+		return "string[]", kindString
+	}
+	kind = attrKind(v)
+	return kind, kind
+}
+
+// neo4jHeader infers the type of every property of the given attribute sets, returning
+// the property names, sorted, along with their header fields.
+func neo4jHeader(attrs []Attr, skip ...string) ([]string, []string, error) {
+	types := make(map[string]string)
+	for _, attr := range attrs {
+		for k, v := range attr {
+			if containsString(skip, k) {
+				continue
+			}
+			if strings.Contains(k, ":") {
+				return nil, nil, fmt.Errorf("Invalid property name %q", k)
+			}
+			typ, kind := neo4jKind(v)
+			if kind == "" {
+				return nil, nil, fmt.Errorf("Unsupported type %T for attribute %q", v, k)
+			}
+			if err := checkAttrRange(k, v); err != nil {
+				return nil, nil, err
+			}
+			current := types[k]
+			if strings.HasSuffix(typ, "[]") || strings.HasSuffix(current, "[]") {
+				if current != "" && current != typ {
+					return nil, nil, fmt.Errorf("Mixed types %s and %s for attribute %q", current, typ, k)
+				}
+				types[k] = typ
+				continue
+			}
+			widened, ok := widenKind(current, kind)
+			if !ok {
+				return nil, nil, fmt.Errorf("Mixed types %s and %s for attribute %q", current, kind, k)
+			}
+			types[k] = widened
+		}
+	}
+
+	names := sortedKinds(types)
+	fields := make([]string, len(names))
+	for i, name := range names {
+		// attribute value types share their name with the Neo4j types
+		fields[i] = name + ":" + types[name]
+	}
+	return names, fields, nil
+}
+
+// neo4jValue formats a property value, joining arrays with the array delimiter.
+func neo4jValue(v interface{}) (string, error) {
+	var items []string
+	switch a := v.(type) {
+	case []bool, []int, []int64, []float32, []float64:
+		// formatted as [v1 v2 ...]
+		s := fmt.Sprint(a)
+		items = strings.Fields(s[1 : len(s)-1])
+	case []string:
+		items = a
+	default:
+		return fmt.Sprint(v), nil
+	}
+	for _, item := range items {
+		if strings.Contains(item, neo4jArrayDelimiter) {
+			return "", fmt.Errorf("Array value %q cannot contain %q", item, neo4jArrayDelimiter)
+		}
+	}
+	return strings.Join(items, neo4jArrayDelimiter), nil
+}
+
+// WriteNeo4jCSV writes the Graph as a pair of Neo4j bulk import files: a node file, with the node id
+// in its :ID column, and a relationship file. Node and edge attributes are written as typed properties,
+// with their type inferred from the values as in WriteGraphML, along with the slices of those types, written
+// as arrays. The Neo4jLabelsKey node attribute ([]string or string) is written to the :LABEL column, and the
+// Neo4jTypeKey edge attribute to the :TYPE column (Neo4jDefaultType if missing). Edge.Weight is written as the
+// Neo4jWeightKey property, which therefore cannot be used as an edge Attr key.
+// In undirected graphs, every edge is written once, as a relationship from the lowest node id.
+func WriteNeo4jCSV(nodes, relationships io.Writer, g *Graph) error {
+	ids := g.sortedNodeIDs()
+	edges := g.sortedEdges()
+
+	nodeAttrs := make([]Attr, len(ids))
+	for i, node := range ids {
+		nodeAttrs[i] = g.nodes[node]
+	}
+	edgeAttrs := make([]Attr, len(edges))
+	for i, edge := range edges {
+		if _, ok := edge.Attr[Neo4jWeightKey]; ok {
+			return fmt.Errorf("Edge attribute %q clashes with the edge weight", Neo4jWeightKey)
+		}
+		edgeAttrs[i] = edge.Attr
+	}
+
+	nodeNames, nodeFields, err := neo4jHeader(nodeAttrs, Neo4jLabelsKey)
+	if err != nil {
+		return err
+	}
+	edgeNames, edgeFields, err := neo4jHeader(edgeAttrs, Neo4jTypeKey)
+	if err != nil {
+		return err
+	}
+
+	// properties returns the values of the given properties, empty if missing
+	properties := func(attr Attr, names []string) ([]string, error) {
+		values := make([]string, len(names))
+		for i, name := range names {
+			if v, ok := attr[name]; ok {
+				s, err := neo4jValue(v)
+				if err != nil {
+					return nil, fmt.Errorf("Attribute %q: %v", name, err)
+				}
+				values[i] = s
+			}
+		}
+		return values, nil
+	}
+
+	cw := csv.NewWriter(nodes)
+	cw.Write(append([]string{":" + neo4jID, ":" + neo4jLabel}, nodeFields...))
+	for i, node := range ids {
+		labels := ""
+		switch l := nodeAttrs[i][Neo4jLabelsKey].(type) {
+		case []string:
+			labels = strings.Join(l, neo4jArrayDelimiter)
+		case string:
+			labels = l
+		}
+		values, err := properties(nodeAttrs[i], nodeNames)
+		if err != nil {
+			return fmt.Errorf("Node %d: %v", node, err)
+		}
+		cw.Write(append([]string{strconv.FormatUint(node, 10), labels}, values...))
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+
+	cw = csv.NewWriter(relationships)
+	header := []string{":" + neo4jStartID, ":" + neo4jEndID, ":" + neo4jType, Neo4jWeightKey + ":long"}
+	cw.Write(append(header, edgeFields...))
+	for _, edge := range edges {
+		typ := Neo4jDefaultType
+		if t, ok := edge.Attr[Neo4jTypeKey]; ok {
+			typ = fmt.Sprint(t)
+		}
+		values, err := properties(edge.Attr, edgeNames)
+		if err != nil {
+			return fmt.Errorf("Edge %d-%d: %v", edge.u, edge.v, err)
+		}
+		record := []string{strconv.FormatUint(edge.u, 10), strconv.FormatUint(edge.v, 10), typ, strconv.Itoa(edge.Weight)}
+		cw.Write(append(record, values...))
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,115 @@
+package grapho
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadNeo4jCSV(t *testing.T) {
+	nodes := `personId:ID(Person),name,age:int,born:long,score:double,active:boolean,tags:string[],:LABEL,skip:IGNORE
+alice,Alice,30,1993,4.5,true,a;b,Person;Admin,x
+bob,Bob,,1990,,false,,Person,y
+carol,"Carol, Jr.",25,,1.0,,c,,z
+`
+	relationships := `:START_ID(Person),:END_ID(Person),:TYPE,weight:int,since:long
+alice,bob,KNOWS,3,2010
+bob,carol,KNOWS,,
+carol,alice,FOLLOWS,2,2020
+`
+	g, ids, err := ReadNeo4jCSV(strings.NewReader(nodes), strings.NewReader(relationships))
+	if err != nil {
+		t.Fatalf("ReadNeo4jCSV: %v", err)
+	}
+	if !g.IsDirected() || g.Len() != 3 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", g.IsDirected(), g.Len())
+	}
+	if ids["alice"] != 1 || ids["bob"] != 2 || ids["carol"] != 3 {
+		t.Errorf("Unexpected ids: %v", ids)
+	}
+
+	attr, _ := g.Node(1)
+	labels, _ := attr[Neo4jLabelsKey].([]string)
+	tags, _ := attr["tags"].([]string)
+	if attr["personId"] != "alice" || attr["name"] != "Alice" || attr["age"] != 30 || attr["born"] != 1993 ||
+		attr["score"] != 4.5 || attr["active"] != true || len(tags) != 2 || tags[1] != "b" ||
+		len(labels) != 2 || labels[1] != "Admin" {
+		t.Errorf("Unexpected attributes for alice: %v", attr)
+	}
+	if _, ok := attr["skip"]; ok {
+		t.Error("Ignored column was imported")
+	}
+	attr, _ = g.Node(2)
+	if _, ok := attr["age"]; ok {
+		t.Errorf("Empty value was imported: %v", attr)
+	}
+	if attr, _ := g.Node(3); attr["name"] != "Carol, Jr." {
+		t.Errorf("Unexpected attributes for carol: %v", attr)
+	}
+
+	edge, ok := g.Edge(1, 2)
+	if !ok || edge.Weight != 3 || edge.Attr[Neo4jTypeKey] != "KNOWS" || edge.Attr["since"] != 2010 {
+		t.Errorf("Unexpected edge alice-bob: %v", edge)
+	}
+	if edge, ok := g.Edge(2, 3); !ok || edge.Weight != 1 {
+		t.Errorf("Unexpected edge bob-carol: %v", edge)
+	}
+	testEdgeExists(t, g, 2, 1, false)
+
+	for _, tt := range []struct{ nodes, relationships string }{
+		{"id:ID,age:int\na,x\n", ":START_ID,:END_ID\n"},
+		{"id:ID,x:date\na,x\n", ":START_ID,:END_ID\n"},
+		{"id:ID\na\na\n", ":START_ID,:END_ID\n"},
+		{"name\na\n", ":START_ID,:END_ID\n"},
+		{"id:ID\na\n", ":START_ID,:END_ID\na,b\n"},
+		{"id:ID\na\n", ":START_ID,:END_ID,weight:double\na,a,1.5\n"},
+	} {
+		if _, _, err := ReadNeo4jCSV(strings.NewReader(tt.nodes), strings.NewReader(tt.relationships)); err == nil {
+			t.Errorf("Expected error reading %q, %q", tt.nodes, tt.relationships)
+		}
+	}
+}
+
+func TestWriteNeo4jCSV(t *testing.T) {
+	g := NewGraph(false)
+	g.AddNode(1, Attr{Neo4jLabelsKey: []string{"City", "Capital"}, "name": "Madrid", "population": 3300000, "zones": []int{1, 2}})
+	g.AddNode(2, Attr{Neo4jLabelsKey: "City", "name": "Toledo", "population": int64(85000), "area": 232.1})
+	g.AddEdge(2, 1, 72, Attr{Neo4jTypeKey: "ROAD", "toll": false})
+	g.AddEdge(1, 1, 0, nil)
+
+	var nodes, relationships bytes.Buffer
+	if err := WriteNeo4jCSV(&nodes, &relationships, g); err != nil {
+		t.Fatalf("WriteNeo4jCSV: %v", err)
+	}
+	expected := `:ID,:LABEL,area:double,name:string,population:long,zones:long[]
+1,City;Capital,,Madrid,3300000,1;2
+2,City,232.1,Toledo,85000,
+`
+	if nodes.String() != expected {
+		t.Errorf("Unexpected node file:\n%s", nodes.String())
+	}
+	expected = `:START_ID,:END_ID,:TYPE,weight:long,toll:boolean
+1,1,RELATED,0,
+1,2,ROAD,72,false
+`
+	if relationships.String() != expected {
+		t.Errorf("Unexpected relationship file:\n%s", relationships.String())
+	}
+
+	h, _, err := ReadNeo4jCSV(&nodes, &relationships)
+	if err != nil {
+		t.Fatalf("ReadNeo4jCSV: %v", err)
+	}
+	attr, _ := h.Node(1)
+	if zones, ok := attr["zones"].([]int); !ok || !equalInts(zones, []int{1, 2}) || attr["population"] != 3300000 {
+		t.Errorf("Unexpected attributes for node 1: %v", attr)
+	}
+	if edge, ok := h.Edge(1, 2); !ok || edge.Weight != 72 || edge.Attr["toll"] != false {
+		t.Errorf("Unexpected edge 1-2: %v", edge)
+	}
+
+	g.AddNode(3, Attr{"name": []string{"a;b"}})
+	if err := WriteNeo4jCSV(&nodes, &relationships, g); err == nil {
+		t.Error("Expected error with mixed attribute types")
+	}
+}
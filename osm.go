@@ -0,0 +1,225 @@
+// OpenStreetMap XML
+// https://wiki.openstreetmap.org/wiki/OSM_XML
+
+package grapho
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Node Attr keys holding the coordinates, in degrees, of OpenStreetMap nodes.
+const (
+	LatKey = "lat"
+	LonKey = "lon"
+)
+
+// OSMWayKey is the edge Attr key holding the id of the OpenStreetMap way an edge belongs to.
+const OSMWayKey = "way"
+
+// earthRadius is the mean Earth radius, in meters.
+const earthRadius = 6371008.8
+
+// OSMOptions configures how OpenStreetMap data is turned into a Graph.
+type OSMOptions struct {
+	Filter       func(tags map[string]string) bool // Ways to import. Defaults to ways with a "highway" tag, other than areas
+	Tags         []string                          // Way tags to store in the Attr of their edges
+	IgnoreOneway bool                              // Build an undirected graph, ignoring oneway restrictions
+}
+
+// osmHighway is the default way filter, keeping roads and paths.
+func osmHighway(tags map[string]string) bool {
+	_, ok := tags["highway"]
+	return ok && tags["area"] != "yes"
+}
+
+type osmWay struct {
+	ID  int64 `xml:"id,attr"`
+	Nds []struct {
+		Ref int64 `xml:"ref,attr"`
+	} `xml:"nd"`
+	Tags []struct {
+		K string `xml:"k,attr"`
+		V string `xml:"v,attr"`
+	} `xml:"tag"`
+}
+
+// osmOneway returns the direction in which a way can be traversed:
+// 1 (forward only), -1 (backward only) or 0 (both directions).
+func osmOneway(tags map[string]string) int {
+	switch tags["oneway"] {
+	case "yes", "true", "1":
+		return 1
+	case "-1", "reverse":
+		return -1
+	case "no", "false", "0":
+		return 0
+	}
+	// implied oneway
+	if tags["junction"] == "roundabout" || tags["highway"] == "motorway" {
+		return 1
+	}
+	return 0
+}
+
+// ReadOSM builds a routable Graph from an OpenStreetMap XML document, reading it as a stream.
+// Every pair of consecutive nodes of the selected ways becomes an edge, weighted with their haversine
+// distance in meters, rounded up. The graph is directed, with edges in both directions unless the way is
+// oneway (either tagged with oneway=yes/-1, or implied for roundabouts and motorways), or undirected if
+// opts.IgnoreOneway is set. Edges hold the way id in their OSMWayKey attribute, along with the opts.Tags
+// way tags. Only the nodes of the selected ways are imported, with the OSM node id as node id and their
+// coordinates in the LatKey and LonKey attributes, as float64. Way nodes missing from the document
+// (i.e. in clipped extracts) are skipped, along with their segments. Relations are ignored.
+func ReadOSM(r io.Reader, opts *OSMOptions) (*Graph, error) {
+	if opts == nil {
+		opts = &OSMOptions{}
+	}
+	filter := opts.Filter
+	if filter == nil {
+		filter = osmHighway
+	}
+
+	g := NewGraph(!opts.IgnoreOneway)
+	coords := make(map[uint64][2]float64)
+
+	// osmID parses an OSM id, which must be positive
+	osmID := func(kind string, id int64) (uint64, error) {
+		if id <= 0 {
+			return 0, fmt.Errorf("Unsupported %s id %d", kind, id)
+		}
+		return uint64(id), nil
+	}
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "node":
+			var id int64
+			var lat, lon float64
+			for _, a := range start.Attr {
+				switch a.Name.Local {
+				case "id":
+					id, err = strconv.ParseInt(a.Value, 10, 64)
+				case "lat":
+					lat, err = strconv.ParseFloat(a.Value, 64)
+				case "lon":
+					lon, err = strconv.ParseFloat(a.Value, 64)
+				}
+				if err != nil {
+					return nil, fmt.Errorf("Node %d: invalid %s %q", id, a.Name.Local, a.Value)
+				}
+			}
+			node, err := osmID("node", id)
+			if err != nil {
+				return nil, err
+			}
+			coords[node] = [2]float64{lat, lon}
+			if err := dec.Skip(); err != nil {
+				return nil, err
+			}
+
+		case "way":
+			var way osmWay
+			if err := dec.DecodeElement(&way, &start); err != nil {
+				return nil, err
+			}
+			wayID, err := osmID("way", way.ID)
+			if err != nil {
+				return nil, err
+			}
+			tags := make(map[string]string, len(way.Tags))
+			for _, tag := range way.Tags {
+				tags[tag.K] = tag.V
+			}
+			if !filter(tags) {
+				continue
+			}
+
+			oneway := 0
+			if !opts.IgnoreOneway {
+				oneway = osmOneway(tags)
+			}
+			attr := func() Attr {
+				attr := Attr{OSMWayKey: wayID}
+				for _, k := range opts.Tags {
+					if v, ok := tags[k]; ok {
+						attr[k] = v
+					}
+				}
+				return attr
+			}
+
+			for i := 1; i < len(way.Nds); i++ {
+				u, erru := osmID("node", way.Nds[i-1].Ref)
+				v, errv := osmID("node", way.Nds[i].Ref)
+				if erru != nil || errv != nil {
+					return nil, fmt.Errorf("Way %d: invalid node reference", wayID)
+				}
+				cu, oku := coords[u]
+				cv, okv := coords[v]
+				if !oku || !okv {
+					continue
+				}
+
+				g.AddNodeIfAbsent(u, Attr{LatKey: cu[0], LonKey: cu[1]})
+				g.AddNodeIfAbsent(v, Attr{LatKey: cv[0], LonKey: cv[1]})
+				weight := int(math.Ceil(haversine(cu[0], cu[1], cv[0], cv[1])))
+				if oneway >= 0 {
+					g.AddEdge(u, v, weight, attr())
+				}
+				if oneway <= 0 && g.directed {
+					g.AddEdge(v, u, weight, attr())
+				}
+			}
+
+		case "relation":
+			if err := dec.Skip(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return g, nil
+}
+
+// haversine returns the great-circle distance, in meters, between two points given in degrees.
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dlat := (lat2 - lat1) * rad
+	dlon := (lon2 - lon1) * rad
+	a := math.Sin(dlat/2)*math.Sin(dlat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dlon/2)*math.Sin(dlon/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// HaversineHeuristic returns an A* Heuristic estimating the cost between two nodes as their haversine
+// distance in meters (rounded down), computed from their LatKey and LonKey attributes, as set by ReadOSM.
+// Since edge weights are distances rounded up, the estimate never exceeds the actual path cost.
+// Nodes without coordinates are estimated with 0.
+func HaversineHeuristic(g *Graph) Heuristic {
+	return func(node, goal uint64) int {
+		na, _ := g.Node(node)
+		ga, _ := g.Node(goal)
+		nlat, ok1 := na[LatKey].(float64)
+		nlon, ok2 := na[LonKey].(float64)
+		glat, ok3 := ga[LatKey].(float64)
+		glon, ok4 := ga[LonKey].(float64)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			return 0
+		}
+		return int(math.Floor(haversine(nlat, nlon, glat, glon)))
+	}
+}
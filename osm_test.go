@@ -0,0 +1,107 @@
+package grapho
+
+import (
+	"strings"
+	"testing"
+)
+
+const osmSample = `<?xml version="1.0" encoding="UTF-8"?>
+<osm version="0.6" generator="test">
+  <bounds minlat="40.0" minlon="-3.0" maxlat="40.1" maxlon="-2.9"/>
+  <node id="1" lat="40.0000" lon="-3.0000"/>
+  <node id="2" lat="40.0010" lon="-3.0000"/>
+  <node id="3" lat="40.0010" lon="-2.9990">
+    <tag k="highway" v="traffic_signals"/>
+  </node>
+  <node id="4" lat="40.0000" lon="-2.9990"/>
+  <node id="5" lat="40.0500" lon="-2.9500"/>
+  <way id="100">
+    <nd ref="1"/>
+    <nd ref="2"/>
+    <nd ref="3"/>
+    <tag k="highway" v="residential"/>
+    <tag k="name" v="Main Street"/>
+  </way>
+  <way id="101">
+    <nd ref="3"/>
+    <nd ref="4"/>
+    <nd ref="1"/>
+    <tag k="highway" v="primary"/>
+    <tag k="oneway" v="yes"/>
+  </way>
+  <way id="102">
+    <nd ref="4"/>
+    <nd ref="99"/>
+    <nd ref="2"/>
+    <tag k="highway" v="service"/>
+    <tag k="oneway" v="-1"/>
+  </way>
+  <way id="103">
+    <nd ref="4"/>
+    <nd ref="5"/>
+    <tag k="building" v="yes"/>
+  </way>
+  <relation id="200">
+    <member type="way" ref="100" role=""/>
+    <tag k="type" v="route"/>
+  </relation>
+</osm>`
+
+func TestReadOSM(t *testing.T) {
+	g, err := ReadOSM(strings.NewReader(osmSample), &OSMOptions{Tags: []string{"name"}})
+	if err != nil {
+		t.Fatalf("ReadOSM: %v", err)
+	}
+	if !g.IsDirected() || g.Len() != 4 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", g.IsDirected(), g.Len())
+	}
+	if attr, _ := g.Node(2); attr[LatKey] != 40.001 || attr[LonKey] != -3.0 {
+		t.Errorf("Unexpected attributes for node 2: %v", attr)
+	}
+
+	// ~111m between 1 and 2 (0.001 degrees of latitude)
+	edge, ok := g.Edge(1, 2)
+	if !ok || edge.Weight != 112 || edge.Attr[OSMWayKey] != uint64(100) || edge.Attr["name"] != "Main Street" {
+		t.Errorf("Unexpected edge 1-2: %v", edge)
+	}
+	testEdgeExists(t, g, 2, 1, true)
+	testEdgeExists(t, g, 3, 4, true)
+	testEdgeExists(t, g, 4, 3, false)
+	testEdgeExists(t, g, 4, 1, true)
+	testEdgeExists(t, g, 1, 4, false)
+	// way 102 references a missing node, and way 103 is not a highway
+	testEdgeExists(t, g, 4, 2, false)
+	testEdgeExists(t, g, 2, 4, false)
+
+	h := HaversineHeuristic(g)
+	if d := h(1, 2); d != 111 {
+		t.Errorf("Heuristic(1, 2): %d. Expected 111", d)
+	}
+	path, err := Search(g, 4, 3, Astar, h)
+	if err != nil || !equalPath(path, []uint64{4, 1, 2, 3}) {
+		t.Errorf("Unexpected path: %v (%v)", path, err)
+	}
+
+	g, err = ReadOSM(strings.NewReader(osmSample), &OSMOptions{IgnoreOneway: true})
+	if err != nil {
+		t.Fatalf("ReadOSM: %v", err)
+	}
+	if g.IsDirected() {
+		t.Error("Expected an undirected graph")
+	}
+	testEdgeExists(t, g, 1, 4, true)
+
+	all := func(tags map[string]string) bool { return true }
+	g, err = ReadOSM(strings.NewReader(osmSample), &OSMOptions{Filter: all})
+	if err != nil {
+		t.Fatalf("ReadOSM: %v", err)
+	}
+	testEdgeExists(t, g, 4, 5, true)
+
+	if _, err := ReadOSM(strings.NewReader(`<osm><node id="-1" lat="0" lon="0"/></osm>`), nil); err == nil {
+		t.Error("Expected error with a negative node id")
+	}
+	if _, err := ReadOSM(strings.NewReader(`<osm><node id="1" lat="x" lon="0"/></osm>`), nil); err == nil {
+		t.Error("Expected error with an invalid latitude")
+	}
+}
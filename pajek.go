@@ -0,0 +1,249 @@
+// Pajek NET format
+// http://mrvar.fdv.uni-lj.si/pajek/DrawEPS.htm
+
+package grapho
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Node Attr keys holding the vertex coordinates of Pajek files.
+const (
+	PajekXKey = "x"
+	PajekYKey = "y"
+	PajekZKey = "z"
+)
+
+// pajekFields splits a Pajek line into fields, keeping quoted strings (without the quotes) as a single field.
+func pajekFields(line string) ([]string, error) {
+	var fields []string
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return fields, nil
+		}
+		if line[0] == '"' {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return nil, errors.New("Unterminated string")
+			}
+			fields = append(fields, line[1:end+1])
+			line = line[end+2:]
+			continue
+		}
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			end = len(line)
+		}
+		fields = append(fields, line[:end])
+		line = line[end:]
+	}
+}
+
+// parseWeight parses an integer weight, accepting reals with no fractional part.
+func parseWeight(s string) (int, error) {
+	if w, err := strconv.Atoi(s); err == nil {
+		return w, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt32 {
+		return 0, fmt.Errorf("Invalid integer weight %q", s)
+	}
+	return int(f), nil
+}
+
+type pajekLine struct {
+	line   int
+	fields []string
+}
+
+// ReadPajek builds a Graph from a Pajek NET file. Vertices are given their Pajek number as node id,
+// with their label stored in the LabelKey attribute and their coordinates (if present) in the PajekXKey,
+// PajekYKey and PajekZKey attributes, as float64. Other vertex parameters are ignored.
+// Lines of the *Arcs and *Edges sections (and their *Arcslist and *Edgeslist variants) become edges,
+// with their optional value, which must be an integer, as Edge.Weight (1 by default). The graph is directed
+// if any arcs section is present, in which case every line of the edges sections yields two edges.
+// Files declaring over 2^20 more vertices than their lines reference are rejected.
+func ReadPajek(r io.Reader) (*Graph, error) {
+	var n, verticesLine int
+	var section string
+	var vertices, arcs, edges, arcslist, edgeslist []pajekLine
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "%") {
+			continue
+		}
+
+		if strings.HasPrefix(text, "*") {
+			fields := strings.Fields(text)
+			section = strings.ToLower(fields[0])
+			switch section {
+			case "*vertices":
+				if len(fields) < 2 {
+					return nil, &ParseError{line, errors.New("Missing number of vertices")}
+				}
+				var err error
+				if n, err = strconv.Atoi(fields[1]); err != nil || n < 0 {
+					return nil, &ParseError{line, fmt.Errorf("Invalid number of vertices %q", fields[1])}
+				}
+				verticesLine = line
+			case "*arcs", "*edges", "*arcslist", "*edgeslist", "*network":
+			default:
+				return nil, &ParseError{line, fmt.Errorf("Unsupported section %q", fields[0])}
+			}
+			continue
+		}
+
+		fields, err := pajekFields(text)
+		if err != nil {
+			return nil, &ParseError{line, err}
+		}
+		l := pajekLine{line, fields}
+		switch section {
+		case "*vertices":
+			vertices = append(vertices, l)
+		case "*arcs":
+			arcs = append(arcs, l)
+		case "*edges":
+			edges = append(edges, l)
+		case "*arcslist":
+			arcslist = append(arcslist, l)
+		case "*edgeslist":
+			edgeslist = append(edgeslist, l)
+		case "*network":
+		default:
+			return nil, &ParseError{line, errors.New("Line outside of any section")}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	directed := len(arcs) > 0 || len(arcslist) > 0
+	g := NewGraph(directed)
+
+	vertex := func(l pajekLine, s string) (uint64, error) {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 1 || v > n {
+			return 0, &ParseError{l.line, fmt.Errorf("Invalid vertex %q", s)}
+		}
+		return uint64(v), nil
+	}
+
+	for _, l := range vertices {
+		v, err := vertex(l, l.fields[0])
+		if err != nil {
+			return nil, err
+		}
+		g.AddNodeIfAbsent(v, nil)
+		if len(l.fields) > 1 {
+			g.SetNodeAttr(v, LabelKey, l.fields[1])
+		}
+		for i, key := range []string{PajekXKey, PajekYKey, PajekZKey} {
+			if len(l.fields) <= 2+i {
+				break
+			}
+			c, err := strconv.ParseFloat(l.fields[2+i], 64)
+			if err != nil {
+				break // not a coordinate, but a vertex parameter
+			}
+			g.SetNodeAttr(v, key, c)
+		}
+	}
+
+	// addEdges adds the edges of the given lines. Both directions are added if both is set.
+	addEdges := func(lines []pajekLine, list, both bool) error {
+		for _, l := range lines {
+			if len(l.fields) < 2 {
+				return &ParseError{l.line, errors.New("Expected at least 2 vertices")}
+			}
+			u, err := vertex(l, l.fields[0])
+			if err != nil {
+				return err
+			}
+
+			targets, weight := l.fields[1:], 1
+			if !list {
+				targets = l.fields[1:2]
+				if len(l.fields) > 2 {
+					if weight, err = parseWeight(l.fields[2]); err != nil {
+						return &ParseError{l.line, err}
+					}
+				}
+			}
+			for _, s := range targets {
+				v, err := vertex(l, s)
+				if err != nil {
+					return err
+				}
+				g.AddEdge(u, v, weight, nil)
+				if both {
+					g.AddEdge(v, u, weight, nil)
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, section := range []struct {
+		lines      []pajekLine
+		list, both bool
+	}{
+		{arcs, false, false},
+		{edges, false, directed},
+		{arcslist, true, false},
+		{edgeslist, true, directed},
+	} {
+		if err := addEdges(section.lines, section.list, section.both); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := addDeclaredNodes(g, n); err != nil {
+		return nil, &ParseError{verticesLine, err}
+	}
+	return g, nil
+}
+
+// WritePajek writes the Graph as a Pajek NET file, with an *Arcs section for directed graphs or an
+// *Edges section for undirected ones. Vertices are numbered 1..n following the Graph Index order, and
+// labelled with their LabelKey attribute (or their node id, if missing).
+func WritePajek(w io.Writer, g *Graph) error {
+	x := g.Index()
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "*Vertices %d\n", x.Len())
+	for i, node := range x.ids {
+		label := fmt.Sprint(node)
+		if l, ok := g.nodes[node][LabelKey]; ok {
+			label = fmt.Sprint(l)
+		}
+		if strings.ContainsRune(label, '"') {
+			return fmt.Errorf("Node %d: label %q cannot contain quotes", node, label)
+		}
+		fmt.Fprintf(bw, "%d \"%s\"\n", i+1, label)
+	}
+
+	if g.directed {
+		bw.WriteString("*Arcs\n")
+	} else {
+		bw.WriteString("*Edges\n")
+	}
+	for _, edge := range g.sortedEdges() {
+		u, _ := x.Pos(edge.u)
+		v, _ := x.Pos(edge.v)
+		fmt.Fprintf(bw, "%d %d %d\n", u+1, v+1, edge.Weight)
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,110 @@
+package grapho
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadPajek(t *testing.T) {
+	src := `% sample network
+*Network sample
+*Vertices 4
+1 "Node A" 0.1 0.2 0.5
+2 "B" ic Red
+3 C
+*Arcs
+1 2 3
+2 3 2.0
+*edges
+3 4
+*Arcslist
+4 1 2
+`
+	g, err := ReadPajek(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("ReadPajek: %v", err)
+	}
+	if !g.IsDirected() || g.Len() != 4 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", g.IsDirected(), g.Len())
+	}
+
+	attr, _ := g.Node(1)
+	if attr[LabelKey] != "Node A" || attr[PajekXKey] != 0.1 || attr[PajekYKey] != 0.2 || attr[PajekZKey] != 0.5 {
+		t.Errorf("Unexpected attributes for node 1: %v", attr)
+	}
+	attr, _ = g.Node(2)
+	if attr[LabelKey] != "B" || len(attr) != 1 {
+		t.Errorf("Unexpected attributes for node 2: %v", attr)
+	}
+	attr, _ = g.Node(3)
+	if attr[LabelKey] != "C" {
+		t.Errorf("Unexpected attributes for node 3: %v", attr)
+	}
+
+	tests := []struct {
+		u, v   uint64
+		weight int
+	}{
+		{1, 2, 3}, {2, 3, 2}, {3, 4, 1}, {4, 3, 1}, {4, 1, 1}, {4, 2, 1},
+	}
+	for _, tt := range tests {
+		if edge, ok := g.Edge(tt.u, tt.v); !ok || edge.Weight != tt.weight {
+			t.Errorf("Edge %d-%d: %v. Expected weight %d", tt.u, tt.v, edge, tt.weight)
+		}
+	}
+	testEdgeExists(t, g, 2, 1, false)
+
+	for _, src := range []string{
+		"1 2",
+		"*Vertices x",
+		"*Vertices 2\n*Edges\n1 3",
+		"*Vertices 2\n*Edges\n1 2 1.5",
+		"*Vertices 2\n1 \"unterminated",
+		"*Matrix\n1 0",
+		"*Vertices 2000000000\n*Edges\n1 2",
+	} {
+		if _, err := ReadPajek(strings.NewReader(src)); err == nil {
+			t.Errorf("Expected error reading %q", src)
+		}
+	}
+}
+
+func TestWritePajek(t *testing.T) {
+	g := NewGraph(false)
+	g.AddNode(10, Attr{LabelKey: "ten"})
+	g.AddEdge(10, 20, 5, nil)
+	g.AddEdge(20, 30, 1, nil)
+
+	var buf bytes.Buffer
+	if err := WritePajek(&buf, g); err != nil {
+		t.Fatalf("WritePajek: %v", err)
+	}
+	expected := `*Vertices 3
+1 "ten"
+2 "20"
+3 "30"
+*Edges
+1 2 5
+2 3 1
+`
+	if buf.String() != expected {
+		t.Errorf("Unexpected output:\n%s", buf.String())
+	}
+
+	h, err := ReadPajek(&buf)
+	if err != nil {
+		t.Fatalf("ReadPajek: %v", err)
+	}
+	if h.IsDirected() || h.Len() != 3 {
+		t.Fatalf("Unexpected graph: directed=%v, %d nodes", h.IsDirected(), h.Len())
+	}
+	if edge, ok := h.Edge(2, 1); !ok || edge.Weight != 5 {
+		t.Errorf("Edge 2-1: %v. Expected weight 5", edge)
+	}
+
+	g.AddNode(40, Attr{LabelKey: `a "quoted" label`})
+	if err := WritePajek(&buf, g); err == nil {
+		t.Error("Expected error writing a quoted label")
+	}
+}
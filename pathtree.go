@@ -0,0 +1,49 @@
+package grapho
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PathTree holds the paths found from a source node to every node reachable from it
+type PathTree struct {
+	Source uint64
+	Dist   map[uint64]int    // Cost of the path to every reachable node
+	Pred   map[uint64]uint64 // Predecessor of every reachable node in its path. The source is its own predecessor
+}
+
+// ShortestPathTree expands every node reachable from source with the given SearchAlgorithm, returning
+// the tree of paths found, instead of a single path. With Dijkstra (or A*, which expands nodes in
+// the same order without an heuristic) every path in the tree is a shortest path, as long as there are no
+// negative weights. BellmanFord and SPFA support them, reporting negative cycles with a *NegativeCycleError.
+// BreadthFirstSearch yields the paths with the least number of edges instead.
+func ShortestPathTree(g *Graph, source uint64, algo SearchAlgorithm) (*PathTree, error) {
+	if _, ok := g.Node(source); !ok {
+		return nil, fmt.Errorf("Node %d not found", source)
+	}
+
+	closedSet, _, err := search(g, source, source, algo, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &PathTree{
+		Source: source,
+		Dist:   make(map[uint64]int, len(closedSet)),
+		Pred:   make(map[uint64]uint64, len(closedSet)),
+	}
+	for node, state := range closedSet {
+		t.Dist[node] = state.cost
+		t.Pred[node] = state.parent
+	}
+	return t, nil
+}
+
+// PathTo returns the path in the tree from the source to target.
+// If target is not reachable from the source, an error is returned
+func (t *PathTree) PathTo(target uint64) ([]uint64, error) {
+	if _, ok := t.Pred[target]; !ok {
+		return nil, errors.New("Path not found")
+	}
+	return buildPath(t.Source, target, func(node uint64) uint64 { return t.Pred[node] }), nil
+}
@@ -0,0 +1,75 @@
+package grapho
+
+import (
+	"testing"
+)
+
+func TestShortestPathTree(t *testing.T) {
+	g := NewGraph(true)
+	g.AddEdge(0, 1, 4, nil)
+	g.AddEdge(0, 2, 1, nil)
+	g.AddEdge(2, 1, 2, nil)
+	g.AddEdge(1, 3, 5, nil)
+	g.AddEdge(3, 0, 1, nil)
+	g.AddNode(4, nil)
+
+	tree, err := ShortestPathTree(g, 0, Dijkstra)
+	if err != nil {
+		t.Fatalf("ShortestPathTree: %v", err)
+	}
+
+	dist := map[uint64]int{0: 0, 1: 3, 2: 1, 3: 8}
+	if len(tree.Dist) != len(dist) {
+		t.Errorf("Dist: %v. Expected: %v", tree.Dist, dist)
+	}
+	for node, d := range dist {
+		if tree.Dist[node] != d {
+			t.Errorf("Dist[%d]: %d. Expected: %d", node, tree.Dist[node], d)
+		}
+	}
+	if tree.Pred[0] != 0 || tree.Pred[1] != 2 || tree.Pred[3] != 1 {
+		t.Errorf("Unexpected predecessors: %v", tree.Pred)
+	}
+
+	paths := map[uint64][]uint64{0: {0}, 1: {0, 2, 1}, 3: {0, 2, 1, 3}}
+	for target, expected := range paths {
+		path, err := tree.PathTo(target)
+		if err != nil {
+			t.Errorf("PathTo(%d): %v", target, err)
+		} else if !equalPath(path, expected) {
+			t.Errorf("PathTo(%d): %v. Expected: %v", target, path, expected)
+		}
+	}
+	if _, err := tree.PathTo(4); err == nil {
+		t.Error("PathTo(4): Did not get expected error")
+	}
+
+	// Every path in the tree matches Search
+	for _, g := range []*Graph{sampleGraph(), sampleDiGraph()} {
+		tree, err := ShortestPathTree(g, 1, Dijkstra)
+		if err != nil {
+			t.Fatalf("ShortestPathTree: %v", err)
+		}
+		for _, target := range g.Nodes() {
+			result, err := SearchPath(g, 1, target, Dijkstra, nil)
+			if err != nil {
+				t.Fatalf("SearchPath: %v", err)
+			}
+			if tree.Dist[target] != result.Cost {
+				t.Errorf("Dist[%d]: %d. Expected: %d", target, tree.Dist[target], result.Cost)
+			}
+		}
+	}
+
+	tree, err = ShortestPathTree(g, 0, BreadthFirstSearch)
+	if err != nil {
+		t.Fatalf("ShortestPathTree: %v", err)
+	}
+	if path, _ := tree.PathTo(1); !equalPath(path, []uint64{0, 1}) || tree.Dist[1] != 4 {
+		t.Errorf("BreadthFirstSearch path to 1: %v, cost %d. Expected: [0 1], cost 4", path, tree.Dist[1])
+	}
+
+	if _, err := ShortestPathTree(g, 5, Dijkstra); err == nil {
+		t.Error("ShortestPathTree: Did not get expected error")
+	}
+}
@@ -13,11 +13,14 @@ const (
 	DepthFirstSearch
 	Dijkstra
 	Astar
+	BellmanFord // Supports negative weights. The heuristic is ignored
+	SPFA        // Shortest Path Faster Algorithm, a queue-based Bellman-Ford. The heuristic is ignored
 )
 
 // searchstate is a graph position for which its ancestors have been evaluated.
 // Contains the nodeId, its parent int, and the total cost of traversing
-// the graph to reach this position
+// the graph to reach this position. The start node is its own parent, so
+// that every uint64 (including 0) is a valid node id
 type searchstate struct {
 	node, parent uint64
 	cost         int // OpenSet takes int as priority type. TODO: add int64 support?
@@ -46,37 +49,92 @@ type Heuristic func(node, goal uint64) int
 
 func NullHeuristic(node, goal uint64) int { return 0 }
 
+// SearchResult holds the path found by SearchPath, along with some statistics about the search
+type SearchResult struct {
+	Path       []uint64 // Nodes in the path, from start to goal
+	Edges      []*Edge  // Edges in the path. Edges[i] joins Path[i] and Path[i+1]
+	Cost       int      // Sum of the weights of the path edges
+	Expanded   int      // Number of nodes expanded by the search (or scanned, with BellmanFord and SPFA)
+	MaxOpenSet int      // Maximum number of items held by the open set at once
+}
+
+// searchstats holds the statistics gathered while traversing the Graph
+type searchstats struct {
+	expanded, maxOpenSet int
+}
+
 // Search find a path between two nodes. The type of search is determined by the Algorithm algo
-// If the Graph contains no path between the nodes, an error is returned
+// If the Graph contains no path between the nodes, an error is returned. With BellmanFord and SPFA,
+// a negative cycle reachable from start is reported with a *NegativeCycleError
 func Search(graph *Graph, start, goal uint64, algo SearchAlgorithm, heuristic Heuristic) ([]uint64, error) {
-	closedSet := traverse(graph, start, goal, algo, heuristic)
+	result, err := SearchPath(graph, start, goal, algo, heuristic)
+	if err != nil {
+		return nil, err
+	}
+	return result.Path, nil
+}
+
+// SearchPath works as Search, but returns a SearchResult with the path cost, its edges, and the
+// amount of work done by the search, instead of the path alone.
+func SearchPath(graph *Graph, start, goal uint64, algo SearchAlgorithm, heuristic Heuristic) (*SearchResult, error) {
+	closedSet, stats, err := search(graph, start, goal, algo, heuristic)
+	if err != nil {
+		return nil, err
+	}
 
 	if _, ok := closedSet[goal]; ok {
-		// calculate the path
-		path := make([]uint64, 0, len(closedSet))
-		// fetch all the nodes in a descendant way, from goal to start
-		node := goal
-		for node != 0 {
-			path = append(path, node)
-			node = closedSet[node]
-		}
+		path := buildPath(start, goal, func(node uint64) uint64 { return closedSet[node].parent })
 
-		// Reverse the slice
-		for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
-			path[i], path[j] = path[j], path[i]
+		result := &SearchResult{
+			Path:       path,
+			Edges:      make([]*Edge, 0, len(path)-1),
+			Expanded:   stats.expanded,
+			MaxOpenSet: stats.maxOpenSet,
 		}
-
-		return path, nil
+		for i := 1; i < len(path); i++ {
+			edge, _ := graph.Edge(path[i-1], path[i])
+			result.Edges = append(result.Edges, edge)
+			result.Cost += edge.Weight
+		}
+		return result, nil
 	}
 
 	return nil, errors.New("Path not found")
 }
 
-// traverse traverses the Graph with the specified algorithm, returning a map of visited nodes,
-// with a reference to their direct ancestor. If goal and start are the same node, every possible
-// node will be expanded. Otherwise, the traversal will stop when goal is expanded.
-func traverse(graph *Graph, start, goal uint64, algo SearchAlgorithm, heuristic Heuristic) (closedSet map[uint64]uint64) {
-	closedSet = make(map[uint64]uint64)
+// buildPath returns the path between start and goal, fetching the parent of every node, from goal to start
+func buildPath(start, goal uint64, parent func(node uint64) uint64) []uint64 {
+	path := []uint64{}
+	for node := goal; node != start; node = parent(node) {
+		path = append(path, node)
+	}
+	path = append(path, start)
+
+	// Reverse the slice
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// search runs the given algorithm, returning the visited nodes and statistics as traverse does
+func search(graph *Graph, start, goal uint64, algo SearchAlgorithm, heuristic Heuristic) (map[uint64]searchstate, searchstats, error) {
+	switch algo {
+	case BellmanFord:
+		return bellmanFord(graph, start)
+	case SPFA:
+		return spfa(graph, start)
+	}
+	closedSet, stats := traverse(graph, start, goal, algo, heuristic)
+	return closedSet, stats, nil
+}
+
+// traverse traverses the Graph with the specified algorithm, returning a map of visited nodes, with
+// a reference to their direct ancestor (start being its own ancestor) and the cost to reach them, and the
+// statistics of the traversal. If goal and start are the same node, every possible node will be expanded.
+// Otherwise, the traversal will stop when goal is expanded.
+func traverse(graph *Graph, start, goal uint64, algo SearchAlgorithm, heuristic Heuristic) (closedSet map[uint64]searchstate, stats searchstats) {
+	closedSet = make(map[uint64]searchstate)
 
 	if heuristic == nil {
 		heuristic = NullHeuristic
@@ -93,17 +151,22 @@ func traverse(graph *Graph, start, goal uint64, algo SearchAlgorithm, heuristic
 		openSet = &container.PQueue{} // Priority queue if Dijkstra or A*
 	}
 
-	state := &searchstate{start, 0, 0}
+	state := &searchstate{start, start, 0}
 	openSet.Push(state, 0)
+	stats.maxOpenSet = 1
 
 	for openSet.Len() > 0 {
+		if n := openSet.Len(); n > stats.maxOpenSet {
+			stats.maxOpenSet = n
+		}
 		item := openSet.Pop()
 		state = item.(*searchstate)
 
 		// Only consider non expanded nodes (not present in closedSet)
 		if _, ok := closedSet[state.node]; !ok {
 			// Store this node in the closed list, with a reference to its parent
-			closedSet[state.node] = state.parent
+			closedSet[state.node] = *state
+			stats.expanded++
 
 			if state.node == goal && start != goal {
 				return
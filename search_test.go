@@ -38,6 +38,99 @@ func TestSearch(t *testing.T) {
 	testDepthFirstSearch(t, sampleDiGraph())
 }
 
+// TestSearchPath tests the path cost, edges and statistics returned by SearchPath
+func TestSearchPath(t *testing.T) {
+	g := NewGraph(true)
+	g.AddEdge(1, 2, 4, nil)
+	g.AddEdge(1, 3, 1, nil)
+	g.AddEdge(3, 2, 2, nil)
+	g.AddEdge(2, 4, 5, nil)
+	g.AddNode(5, nil)
+
+	result, err := SearchPath(g, 1, 4, Dijkstra, nil)
+	if err != nil {
+		t.Fatalf("SearchPath: %v", err)
+	}
+	expected := []uint64{1, 3, 2, 4}
+	if !equalPath(result.Path, expected) {
+		t.Errorf("Path: %v. Expected: %v", result.Path, expected)
+	}
+	if result.Cost != 8 {
+		t.Errorf("Cost: %d. Expected: 8", result.Cost)
+	}
+	if len(result.Edges) != 3 || result.Edges[0].Weight != 1 || result.Edges[1].Weight != 2 || result.Edges[2].Weight != 5 {
+		t.Errorf("Unexpected edges: %v", result.Edges)
+	}
+	if result.Expanded != 4 {
+		t.Errorf("Expanded: %d. Expected: 4", result.Expanded)
+	}
+	if result.MaxOpenSet != 2 {
+		t.Errorf("MaxOpenSet: %d. Expected: 2", result.MaxOpenSet)
+	}
+
+	// BFS finds the shortest path in hops, which is not the cheapest one
+	result, err = SearchPath(g, 1, 4, BreadthFirstSearch, nil)
+	if err != nil {
+		t.Fatalf("SearchPath: %v", err)
+	}
+	if !equalPath(result.Path, []uint64{1, 2, 4}) || result.Cost != 9 {
+		t.Errorf("Path: %v, cost %d. Expected: [1 2 4], cost 9", result.Path, result.Cost)
+	}
+
+	result, err = SearchPath(g, 1, 1, Dijkstra, nil)
+	if err != nil || !equalPath(result.Path, []uint64{1}) || result.Cost != 0 || len(result.Edges) != 0 {
+		t.Errorf("Unexpected result for a single node path: %v (%v)", result, err)
+	}
+
+	if _, err := SearchPath(g, 1, 5, Dijkstra, nil); err == nil {
+		t.Error("SearchPath: Did not get expected error")
+	}
+}
+
+// TestSearchNodeZero tests that 0 is a valid node id, as start, goal and intermediate node
+func TestSearchNodeZero(t *testing.T) {
+	algorithms := []SearchAlgorithm{BreadthFirstSearch, DepthFirstSearch, Dijkstra, Astar}
+
+	for _, directed := range []bool{false, true} {
+		// 1 - 0 - 2 - 3
+		g := NewGraph(directed)
+		g.AddEdge(1, 0, 1, nil)
+		g.AddEdge(0, 2, 1, nil)
+		g.AddEdge(2, 3, 1, nil)
+
+		tests := []struct {
+			start, goal uint64
+			expected    []uint64
+		}{
+			{0, 3, []uint64{0, 2, 3}},
+			{1, 0, []uint64{1, 0}},
+			{1, 3, []uint64{1, 0, 2, 3}},
+			{0, 0, []uint64{0}},
+		}
+		for _, algo := range algorithms {
+			for _, tt := range tests {
+				path, err := Search(g, tt.start, tt.goal, algo, nil)
+				if err != nil {
+					t.Errorf("Search(%d, %d, %v): %v", tt.start, tt.goal, algo, err)
+				} else if !equalPath(path, tt.expected) {
+					t.Errorf("Search(%d, %d, %v): %v. Expected: %v", tt.start, tt.goal, algo, path, tt.expected)
+				}
+			}
+			if _, err := Search(g, 0, 4, algo, nil); err == nil {
+				t.Errorf("Search(0, 4, %v): Did not get expected error", algo)
+			}
+		}
+
+		if directed {
+			for _, algo := range algorithms {
+				if _, err := Search(g, 3, 0, algo, nil); err == nil {
+					t.Errorf("Search(3, 0, %v): Did not get expected error", algo)
+				}
+			}
+		}
+	}
+}
+
 // testDijkstra tests the Dijkstra algorithm with the given graph
 func testDijkstra(t *testing.T, g *Graph) {
 	expected := []uint64{1, 2, 5, 8}
@@ -0,0 +1,188 @@
+package grapho
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Summary holds a quick profile of a Graph.
+// The degree of a node is the number of edges incident to it: in digraphs, the sum of
+// its in-degree and out-degree, and in undirected graphs self-loops are counted twice.
+type Summary struct {
+	Directed   bool        `json:"directed"`
+	Nodes      int         `json:"nodes"`
+	Edges      int         `json:"edges"`
+	SelfLoops  int         `json:"self_loops"`
+	Isolated   int         `json:"isolated"`
+	MinDegree  int         `json:"min_degree"`
+	MaxDegree  int         `json:"max_degree"`
+	MeanDegree float64     `json:"mean_degree"`
+	Histogram  map[int]int `json:"degree_histogram"` // Number of nodes for each degree
+	Density    float64     `json:"density"`          // Ratio of edges (excluding self-loops) to the maximum possible
+	Connected  bool        `json:"connected"`        // Weakly connected, for digraphs
+}
+
+// Summarize computes the Summary of the given Graph, in a single pass over its edges.
+func Summarize(g *Graph) *Summary {
+	s := &Summary{
+		Directed:  g.directed,
+		Nodes:     len(g.nodes),
+		Histogram: make(map[int]int),
+	}
+
+	degree := make(map[uint64]int, len(g.nodes))
+	sets := newDisjointSet()
+	for u, edges := range g.edges {
+		for v := range edges {
+			if u == v {
+				s.SelfLoops++
+			}
+
+			if g.directed {
+				degree[u]++
+				degree[v]++
+				s.Edges++
+			} else {
+				degree[u]++ // a self-loop is visited once, but counts twice
+				if u == v {
+					degree[u]++
+				}
+				if u <= v {
+					s.Edges++
+				}
+			}
+			sets.union(u, v)
+		}
+	}
+
+	first := true
+	total := 0
+	for node := range g.nodes {
+		sets.add(node)
+
+		d := degree[node]
+		s.Histogram[d]++
+		total += d
+		if d == 0 {
+			s.Isolated++
+		}
+		if first || d < s.MinDegree {
+			s.MinDegree = d
+		}
+		if first || d > s.MaxDegree {
+			s.MaxDegree = d
+		}
+		first = false
+	}
+
+	if s.Nodes > 0 {
+		s.MeanDegree = float64(total) / float64(s.Nodes)
+		s.Connected = sets.count == 1
+	}
+	if s.Nodes > 1 {
+		max := float64(s.Nodes) * float64(s.Nodes-1)
+		if !g.directed {
+			max /= 2
+		}
+		s.Density = float64(s.Edges-s.SelfLoops) / max
+	}
+
+	return s
+}
+
+// String renders the Summary as human readable text.
+func (s *Summary) String() string {
+	var buf bytes.Buffer
+
+	kind := "undirected"
+	if s.Directed {
+		kind = "directed"
+	}
+	fmt.Fprintf(&buf, "Graph (%s)\n", kind)
+	fmt.Fprintf(&buf, "  nodes:      %d\n", s.Nodes)
+	fmt.Fprintf(&buf, "  edges:      %d\n", s.Edges)
+	fmt.Fprintf(&buf, "  self-loops: %d\n", s.SelfLoops)
+	fmt.Fprintf(&buf, "  isolated:   %d\n", s.Isolated)
+	fmt.Fprintf(&buf, "  degree:     min %d, max %d, mean %.2f\n", s.MinDegree, s.MaxDegree, s.MeanDegree)
+	fmt.Fprintf(&buf, "  density:    %.4f\n", s.Density)
+	fmt.Fprintf(&buf, "  connected:  %t\n", s.Connected)
+
+	degrees := make([]int, 0, len(s.Histogram))
+	for d := range s.Histogram {
+		degrees = append(degrees, d)
+	}
+	sort.Ints(degrees)
+
+	buf.WriteString("  histogram:\n")
+	for _, d := range degrees {
+		fmt.Fprintf(&buf, "    %d: %d\n", d, s.Histogram[d])
+	}
+
+	return buf.String()
+}
+
+// JSON renders the Summary as a JSON document.
+func (s *Summary) JSON() ([]byte, error) {
+	return json.Marshal(s)
+}
+
+// disjointSet implements a union-find structure over node ids,
+// keeping track of the number of disjoint sets.
+type disjointSet struct {
+	parent map[uint64]uint64
+	rank   map[uint64]int
+	count  int
+}
+
+func newDisjointSet() *disjointSet {
+	return &disjointSet{
+		parent: make(map[uint64]uint64),
+		rank:   make(map[uint64]int),
+	}
+}
+
+// add creates a singleton set for the given node, if not present yet.
+func (s *disjointSet) add(node uint64) {
+	if _, ok := s.parent[node]; !ok {
+		s.parent[node] = node
+		s.count++
+	}
+}
+
+// find returns the representative of the set containing node, compressing the path.
+func (s *disjointSet) find(node uint64) uint64 {
+	root := node
+	for s.parent[root] != root {
+		root = s.parent[root]
+	}
+	for node != root {
+		next := s.parent[node]
+		s.parent[node] = root
+		node = next
+	}
+	return root
+}
+
+// union merges the sets containing u and v.
+func (s *disjointSet) union(u, v uint64) {
+	s.add(u)
+	s.add(v)
+
+	ru, rv := s.find(u), s.find(v)
+	if ru == rv {
+		return
+	}
+
+	switch {
+	case s.rank[ru] < s.rank[rv]:
+		s.parent[ru] = rv
+	case s.rank[ru] > s.rank[rv]:
+		s.parent[rv] = ru
+	default:
+		s.parent[rv] = ru
+		s.rank[ru]++
+	}
+	s.count--
+}
@@ -0,0 +1,48 @@
+package grapho
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	g := sampleGraph()
+	g.AddEdge(9, 9, 1, nil)
+	g.AddNode(10, nil)
+
+	s := Summarize(g)
+	if s.Nodes != 10 || s.Edges != 13 || s.SelfLoops != 1 || s.Isolated != 1 {
+		t.Errorf("Unexpected counts: %+v", s)
+	}
+	if s.MinDegree != 0 || s.MaxDegree != 4 || s.MeanDegree != 2.6 {
+		t.Errorf("Unexpected degrees: %+v", s)
+	}
+	if s.Histogram[0] != 1 || s.Histogram[2] != 3 || s.Histogram[3] != 4 || s.Histogram[4] != 2 {
+		t.Errorf("Unexpected histogram: %v", s.Histogram)
+	}
+	if s.Density != 12.0/45 {
+		t.Errorf("Density: %v. Expected %v", s.Density, 12.0/45)
+	}
+	if s.Connected {
+		t.Errorf("Graph should not be connected")
+	}
+
+	g.DeleteNode(10)
+	if s = Summarize(g); !s.Connected {
+		t.Errorf("Graph should be connected")
+	}
+
+	s = Summarize(sampleDiGraph())
+	if s.Edges != 24 || s.MaxDegree != 8 || !s.Connected || s.Density != 24.0/72 {
+		t.Errorf("Unexpected digraph summary: %+v", s)
+	}
+
+	data, err := s.JSON()
+	if err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	var decoded Summary
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.Edges != s.Edges {
+		t.Errorf("Unexpected JSON rendering: %s", data)
+	}
+}
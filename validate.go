@@ -0,0 +1,88 @@
+package grapho
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Violation describes a broken structural invariant found by Validate.
+// Node is the offending node. For edge related violations, Peer holds
+// the other endpoint of the edge.
+type Violation struct {
+	Node, Peer uint64
+	Reason     string
+}
+
+func (v Violation) Error() string {
+	return fmt.Sprintf("node %d (peer %d): %s", v.Node, v.Peer, v.Reason)
+}
+
+// Validate checks the structural invariants of the Graph, returning the list
+// of violations found, ordered by node. An empty list means the Graph is consistent:
+//   - Every node has non-nil attributes and an adjacency list, and vice versa.
+//   - Both endpoints of every edge exist, and edges have non-nil attributes.
+//   - In undirected graphs, every u-v edge has a v-u counterpart, sharing the same *Edge.
+func (g *Graph) Validate() []Violation {
+	var violations []Violation
+	add := func(node, peer uint64, reason string) {
+		violations = append(violations, Violation{node, peer, reason})
+	}
+
+	for _, node := range g.sortedNodeIDs() {
+		if g.nodes[node] == nil {
+			add(node, node, "nil node attributes")
+		}
+		if _, ok := g.edges[node]; !ok {
+			add(node, node, "missing adjacency list")
+		}
+	}
+
+	adjacent := make([]uint64, 0, len(g.edges))
+	for node := range g.edges {
+		adjacent = append(adjacent, node)
+	}
+	sort.Sort(uint64Slice(adjacent))
+
+	for _, u := range adjacent {
+		if _, ok := g.nodes[u]; !ok {
+			add(u, u, "adjacency list for non-existent node")
+		}
+
+		succ := make([]uint64, 0, len(g.edges[u]))
+		for v := range g.edges[u] {
+			succ = append(succ, v)
+		}
+		sort.Sort(uint64Slice(succ))
+
+		for _, v := range succ {
+			edge := g.edges[u][v]
+			if edge == nil {
+				add(u, v, "nil edge")
+				continue
+			}
+			if edge.Attr == nil {
+				add(u, v, "nil edge attributes")
+			}
+			if _, ok := g.nodes[v]; !ok {
+				add(u, v, "edge to non-existent node")
+				continue
+			}
+			if !g.directed {
+				if back, ok := g.edges[v][u]; !ok {
+					add(u, v, "undirected edge without its reverse counterpart")
+				} else if back != edge {
+					add(u, v, "undirected edge does not share its *Edge with the reverse counterpart")
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+// sortedNodeIDs returns the list of nodes in the Graph, in ascending order.
+func (g *Graph) sortedNodeIDs() []uint64 {
+	nodes := g.Nodes()
+	sort.Sort(uint64Slice(nodes))
+	return nodes
+}
@@ -0,0 +1,42 @@
+package grapho
+
+import (
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	if v := sampleGraph().Validate(); len(v) != 0 {
+		t.Errorf("Unexpected violations in undirected graph: %v", v)
+	}
+	if v := sampleDiGraph().Validate(); len(v) != 0 {
+		t.Errorf("Unexpected violations in directed graph: %v", v)
+	}
+
+	// Dangling edge after deleting a node in a digraph
+	g := NewGraph(true)
+	g.AddEdge(1, 2, 1, nil)
+	g.DeleteNode(2)
+	v := g.Validate()
+	if len(v) != 1 || v[0].Node != 1 || v[0].Peer != 2 {
+		t.Errorf("Expected dangling edge 1-2 violation. Got %v", v)
+	}
+
+	// Asymmetric undirected edge
+	g = NewGraph(false)
+	g.AddEdge(1, 2, 1, nil)
+	delete(g.edges[2], 1)
+	v = g.Validate()
+	if len(v) != 1 || v[0].Node != 1 || v[0].Peer != 2 {
+		t.Errorf("Expected asymmetric edge 1-2 violation. Got %v", v)
+	}
+
+	// Undirected edge with different *Edge on each direction, and nil attributes
+	g = NewGraph(false)
+	g.AddEdge(1, 2, 1, nil)
+	g.edges[2][1] = &Edge{1, nil}
+	g.nodes[1] = nil
+	v = g.Validate()
+	if len(v) != 4 {
+		t.Errorf("Expected 4 violations. Got %v", v)
+	}
+}